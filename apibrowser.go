@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const apiResourcesMenuName = "API Resources"
+
+// coreGroup is how the unnamed API group of pods, services and the like is
+// shown in the menu.
+const coreGroup = "core"
+
+// qualifiedName returns the resource as it is passed to oc, with its group so
+// that kinds served by several groups (events, for one) are unambiguous.
+func (r apiResource) qualifiedName() string {
+	if r.Group == "" {
+		return r.Name
+	}
+	return r.Name + "." + r.Group
+}
+
+// groupVersion returns the apiVersion of the resource, such as "apps/v1".
+func (r apiResource) groupVersion() string {
+	if r.Group == "" {
+		return r.Version
+	}
+	return r.Group + "/" + r.Version
+}
+
+// supports reports whether the resource allows verb. Resources listed
+// without verbs are assumed to allow everything.
+func (r apiResource) supports(verb string) bool {
+	return len(r.Verbs) == 0 || matchesAny(r.Verbs, verb)
+}
+
+// apiResourceActions returns the menu items offered for one resource type.
+func apiResourceActions(r apiResource) []*MenuItem {
+	ref := r.qualifiedName()
+	var actions []*MenuItem
+	if r.supports("list") {
+		actions = append(actions,
+			&MenuItem{Name: "List", Command: "oc get " + ref, Description: "List " + r.Name, IsExec: true})
+		if r.Namespaced {
+			actions = append(actions,
+				&MenuItem{Name: "List in all projects", Command: "oc get " + ref + " -A", Description: "List " + r.Name + " in every project", IsExec: true})
+		}
+		actions = append(actions,
+			&MenuItem{Name: "YAML", Command: "oc get " + ref + " -o yaml", Description: "Show all " + r.Name + " as YAML", IsExec: true})
+	}
+	if r.supports("get") {
+		actions = append(actions,
+			&MenuItem{Name: "Describe", Command: "oc describe " + ref, Description: "Describe all " + r.Name, IsExec: true})
+	}
+	actions = append(actions,
+		&MenuItem{Name: "Explain", Command: "oc explain " + ref, Description: "Show the schema of " + r.Kind, IsExec: true})
+	return actions
+}
+
+// apiGroupMenus builds one submenu per API group from resources, the core
+// group first and the others sorted by name.
+func apiGroupMenus(resources []apiResource) []*MenuItem {
+	byGroup := make(map[string][]apiResource)
+	for _, r := range resources {
+		group := r.Group
+		if group == "" {
+			group = coreGroup
+		}
+		byGroup[group] = append(byGroup[group], r)
+	}
+
+	groups := make([]string, 0, len(byGroup))
+	for group := range byGroup {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if (groups[i] == coreGroup) != (groups[j] == coreGroup) {
+			return groups[i] == coreGroup
+		}
+		return groups[i] < groups[j]
+	})
+
+	menus := make([]*MenuItem, 0, len(groups))
+	for _, group := range groups {
+		kinds := byGroup[group]
+		sort.Slice(kinds, func(i, j int) bool { return kinds[i].Kind < kinds[j].Kind })
+
+		submenu := make([]*MenuItem, 0, len(kinds))
+		for _, r := range kinds {
+			description := r.Name + " (" + r.groupVersion() + ")"
+			if len(r.ShortNames) > 0 {
+				description += ", short: " + strings.Join(r.ShortNames, ",")
+			}
+			submenu = append(submenu, &MenuItem{Name: r.Kind, Description: description, Submenu: apiResourceActions(r)})
+		}
+		menus = append(menus, &MenuItem{
+			Name:        group,
+			Description: fmt.Sprintf("%d kinds", len(kinds)),
+			Submenu:     submenu,
+		})
+	}
+	return menus
+}
+
+// apiResourcesMenu builds the menu over every resource type the cluster
+// serves, split into namespaced and cluster-scoped kinds and grouped by API
+// group. It returns nil until the API resources have been discovered.
+func (nav *OCNavigator) apiResourcesMenu() *MenuItem {
+	if nav.apis == nil || len(nav.apis.Resources) == 0 {
+		return nil
+	}
+
+	var namespaced, clusterScoped []apiResource
+	for _, r := range nav.apis.Resources {
+		if r.Namespaced {
+			namespaced = append(namespaced, r)
+		} else {
+			clusterScoped = append(clusterScoped, r)
+		}
+	}
+
+	var submenu []*MenuItem
+	if len(namespaced) > 0 {
+		submenu = append(submenu, &MenuItem{
+			Name:        "Namespaced",
+			Description: fmt.Sprintf("%d kinds that live in a project", len(namespaced)),
+			Submenu:     apiGroupMenus(namespaced),
+		})
+	}
+	if len(clusterScoped) > 0 {
+		submenu = append(submenu, &MenuItem{
+			Name:        "Cluster-scoped",
+			Description: fmt.Sprintf("%d kinds that belong to the whole cluster", len(clusterScoped)),
+			Submenu:     apiGroupMenus(clusterScoped),
+		})
+	}
+	return &MenuItem{
+		Name:        apiResourcesMenuName,
+		Description: "Browse every resource type served by the cluster, including CRDs",
+		Submenu:     submenu,
+	}
+}
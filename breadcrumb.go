@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// breadcrumbs returns the names along the current navigation path, starting
+// with "Home" for the main menu and ending with the item that was last run
+// and the resource selected in the live table, if any.
+func (nav *OCNavigator) breadcrumbs() []string {
+	crumbs := []string{"Home"}
+	for _, title := range nav.titleStack[min(1, len(nav.titleStack)):] {
+		crumbs = append(crumbs, strings.TrimSpace(title))
+	}
+	if len(nav.menuStack) > 0 {
+		crumbs = append(crumbs, strings.TrimSpace(nav.menuList.GetTitle()))
+	}
+
+	if nav.activeItem != nil {
+		crumbs = append(crumbs, nav.activeItem.Name)
+	}
+	if nav.live != nil {
+		row, _ := nav.resourceTable.GetSelection()
+		if row > 0 && row <= len(nav.live.keys) {
+			key := nav.live.keys[row-1]
+			crumbs = append(crumbs, key[strings.Index(key, "/")+1:])
+		}
+	}
+	return crumbs
+}
+
+// updateBreadcrumb redraws the breadcrumb bar above the panes. Menu levels
+// are numbered so they can be reached directly with Alt+<n>.
+func (nav *OCNavigator) updateBreadcrumb() {
+	crumbs := nav.breadcrumbs()
+	parts := make([]string, len(crumbs))
+	for i, crumb := range crumbs {
+		switch {
+		case i > len(nav.menuStack):
+			parts[i] = tview.Escape(crumb)
+		case i == len(nav.menuStack):
+			parts[i] = nav.theme.paint(roleTitle, fmt.Sprintf("%d:%s", i, tview.Escape(crumb)))
+		default:
+			parts[i] = nav.theme.paint(roleKey, fmt.Sprintf("%d:%s", i, tview.Escape(crumb)))
+		}
+	}
+	nav.breadcrumbBar.SetText(" " + strings.Join(parts, " "+nav.theme.paint(roleMuted, "›")+" "))
+}
+
+// popMenu returns to the parent menu. It reports false when the main menu is
+// already shown.
+func (nav *OCNavigator) popMenu() bool {
+	if len(nav.menuStack) == 0 {
+		return false
+	}
+
+	nav.currentMenu = nav.menuStack[len(nav.menuStack)-1]
+	nav.menuStack = nav.menuStack[:len(nav.menuStack)-1]
+	nav.menuParents = nav.menuParents[:len(nav.menuParents)-1]
+
+	if len(nav.titleStack) > 0 {
+		nav.menuList.SetTitle(nav.titleStack[len(nav.titleStack)-1])
+		nav.titleStack = nav.titleStack[:len(nav.titleStack)-1]
+	} else {
+		nav.menuList.SetTitle(" Navigation ")
+	}
+
+	nav.activeItem = nil
+	nav.populateMenu()
+	nav.updateBreadcrumb()
+	return true
+}
+
+// jumpToLevel pops menus until the menu at depth level is shown; level 0 is
+// the main menu.
+func (nav *OCNavigator) jumpToLevel(level int) {
+	for len(nav.menuStack) > level {
+		nav.popMenu()
+	}
+}
+
+// openMenuPath navigates to a slash separated path of menu item names such
+// as "Workloads/Pods", entering the last item when it is a submenu.
+func (nav *OCNavigator) openMenuPath(menuPath string) error {
+	path, target, err := resolveMenuPath(nav.rootMenu, menuPath)
+	if err != nil {
+		return err
+	}
+
+	nav.navigateTo(path)
+	if target.Submenu != nil {
+		nav.onMenuSelect(path[len(path)-1], target.Name, target.Description, 0)
+	}
+	return nil
+}
+
+// resolveMenuPath looks up a slash separated path of item names in menu and
+// returns the index path and the item it leads to.
+func resolveMenuPath(menu []*MenuItem, menuPath string) ([]int, *MenuItem, error) {
+	var path []int
+	var target *MenuItem
+
+	for _, name := range strings.Split(strings.Trim(menuPath, "/"), "/") {
+		if menu == nil {
+			return nil, nil, fmt.Errorf("menu item %q has no submenu", target.Name)
+		}
+		index := findMenuItem(menu, strings.TrimSpace(name))
+		if index < 0 {
+			return nil, nil, fmt.Errorf("no menu item named %q", name)
+		}
+		target = menu[index]
+		path = append(path, index)
+		menu = target.Submenu
+	}
+	return path, target, nil
+}
+
+// findMenuItem returns the index of the item called name in menu, ignoring
+// case, or -1 if there is none.
+func findMenuItem(menu []*MenuItem, name string) int {
+	for i, item := range menu {
+		if strings.EqualFold(item.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
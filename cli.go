@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// cliTool is the command line tool that runs every command. Commands are
+// written for oc and translated when the tool is kubectl.
+type cliTool struct {
+	path    string
+	kubectl bool
+}
+
+// openShiftOnly are oc commands that have no kubectl equivalent.
+var openShiftOnly = map[string]bool{
+	"login": true, "logout": true, "new-app": true, "new-build": true, "start-build": true,
+	"cancel-build": true, "import-image": true, "tag": true, "process": true, "policy": true,
+	"adm": true, "rsh": true, "rsync": true, "image": true, "registry": true, "idle": true,
+	"observe": true, "status": true, "extract": true,
+}
+
+// findCLI resolves the -cli value: "oc", "kubectl" or a path to either. When
+// nothing was asked for explicitly and oc is missing, kubectl is used.
+func findCLI(spec string, explicit bool) (cliTool, error) {
+	path, err := exec.LookPath(spec)
+	if err != nil && !explicit {
+		if kubectl, kerr := exec.LookPath("kubectl"); kerr == nil {
+			path, spec, err = kubectl, "kubectl", nil
+		}
+	}
+	if err != nil {
+		return cliTool{}, fmt.Errorf("%s not found: install the OpenShift CLI or pass -cli kubectl", spec)
+	}
+	return cliTool{path: path, kubectl: strings.Contains(filepath.Base(spec), "kubectl")}, nil
+}
+
+// name is how the tool is shown to the user.
+func (c cliTool) name() string {
+	if c.kubectl {
+		return "kubectl"
+	}
+	return "oc"
+}
+
+// translate rewrites the arguments of an oc command for the tool. OpenShift
+// commands without an equivalent are reported as errors.
+func (c cliTool) translate(args []string) ([]string, error) {
+	if !c.kubectl || len(args) == 0 {
+		return args, nil
+	}
+
+	switch args[0] {
+	case "project":
+		if len(args) == 1 || args[1] == "-q" {
+			return []string{"config", "view", "--minify", "-o", "jsonpath={..namespace}"}, nil
+		}
+		return []string{"config", "set-context", "--current", "--namespace=" + args[1]}, nil
+	case "projects":
+		return []string{"get", "namespaces"}, nil
+	case "new-project":
+		if len(args) < 2 {
+			return nil, fmt.Errorf("new-project needs a name")
+		}
+		return []string{"create", "namespace", args[1]}, nil
+	case "whoami":
+		if len(args) > 1 && args[1] == "--show-server" {
+			return []string{"config", "view", "--minify", "-o", "jsonpath={.clusters[0].cluster.server}"}, nil
+		}
+		return []string{"auth", "whoami", "-o", "jsonpath={.status.userInfo.username}"}, nil
+	case "get", "describe", "delete", "edit":
+		if len(args) > 1 {
+			translated := append([]string(nil), args...)
+			translated[1] = kubectlKind(args[1])
+			return translated, nil
+		}
+	}
+	if openShiftOnly[args[0]] {
+		return nil, fmt.Errorf("\"oc %s\" is OpenShift-only and has no kubectl equivalent", args[0])
+	}
+	return args, nil
+}
+
+// kubectlKind maps OpenShift projects to the namespaces behind them.
+func kubectlKind(kind string) string {
+	name, rest, _ := strings.Cut(kind, "/")
+	switch name {
+	case "project", "projects":
+		name = "namespaces"
+	default:
+		return kind
+	}
+	if rest != "" {
+		return name + "/" + rest
+	}
+	return name
+}
+
+// requiredResource maps a resource needed by an oc command to the one its
+// translation needs.
+func (c cliTool) requiredResource(resource string) string {
+	if c.kubectl && (resource == "projects" || resource == "projectrequests") {
+		return "namespaces"
+	}
+	return resource
+}
+
+// command builds the process for an oc command given without the leading "oc".
+func (c cliTool) command(ctx context.Context, args ...string) (*exec.Cmd, error) {
+	translated, err := c.translate(args)
+	if err != nil {
+		return nil, err
+	}
+	return exec.CommandContext(ctx, c.path, translated...), nil
+}
+
+// display shows command the way it is actually run.
+func (c cliTool) display(command string) string {
+	fields := strings.Fields(command)
+	if len(fields) == 0 || fields[0] != "oc" || !c.kubectl {
+		return command
+	}
+	translated, err := c.translate(fields[1:])
+	if err != nil {
+		return command
+	}
+	return strings.Join(append([]string{"kubectl"}, translated...), " ")
+}
+
+// unsupportedCommand returns why command cannot run with the tool, or an
+// empty string when it can.
+func (c cliTool) unsupportedCommand(command string) string {
+	fields := strings.Fields(command)
+	if len(fields) < 2 || fields[0] != "oc" {
+		return ""
+	}
+	if _, err := c.translate(fields[1:]); err != nil {
+		return err.Error()
+	}
+	return ""
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCLIToolTranslate(t *testing.T) {
+	kubectl := cliTool{path: "kubectl", kubectl: true}
+	tests := []struct {
+		args []string
+		want []string
+		err  string
+	}{
+		{args: []string{"project", "-q"}, want: []string{"config", "view", "--minify", "-o", "jsonpath={..namespace}"}},
+		{args: []string{"project", "demo"}, want: []string{"config", "set-context", "--current", "--namespace=demo"}},
+		{args: []string{"projects"}, want: []string{"get", "namespaces"}},
+		{args: []string{"new-project", "demo"}, want: []string{"create", "namespace", "demo"}},
+		{args: []string{"new-project"}, err: "needs a name"},
+		{args: []string{"whoami"}, want: []string{"auth", "whoami", "-o", "jsonpath={.status.userInfo.username}"}},
+		{args: []string{"whoami", "--show-server"}, want: []string{"config", "view", "--minify", "-o", "jsonpath={.clusters[0].cluster.server}"}},
+		{args: []string{"get", "projects"}, want: []string{"get", "namespaces"}},
+		{args: []string{"delete", "project/demo"}, want: []string{"delete", "namespaces/demo"}},
+		{args: []string{"get", "pods", "-o", "wide"}, want: []string{"get", "pods", "-o", "wide"}},
+		{args: []string{"logs", "web-1"}, want: []string{"logs", "web-1"}},
+		{args: []string{"new-app", "nginx"}, err: "OpenShift-only"},
+		{args: []string{"adm", "top", "nodes"}, err: "OpenShift-only"},
+	}
+	for _, tt := range tests {
+		got, err := kubectl.translate(tt.args)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("translate(%q) error = %v, want %q", tt.args, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("translate(%q) = %q, %v, want %q", tt.args, got, err, tt.want)
+		}
+	}
+}
+
+func TestCLIToolTranslateOC(t *testing.T) {
+	oc := cliTool{path: "oc"}
+	for _, args := range [][]string{{"project", "-q"}, {"new-app", "nginx"}, {"get", "projects"}} {
+		got, err := oc.translate(args)
+		if err != nil || !reflect.DeepEqual(got, args) {
+			t.Errorf("oc translate(%q) = %q, %v, want it unchanged", args, got, err)
+		}
+	}
+}
+
+func TestCLIToolDisplay(t *testing.T) {
+	kubectl := cliTool{path: "/usr/local/bin/kubectl", kubectl: true}
+	tests := map[string]string{
+		"oc get projects":  "kubectl get namespaces",
+		"oc get pods -A":   "kubectl get pods -A",
+		"oc new-app nginx": "oc new-app nginx",
+		"ls -l":            "ls -l",
+		"":                 "",
+	}
+	for command, want := range tests {
+		if got := kubectl.display(command); got != want {
+			t.Errorf("display(%q) = %q, want %q", command, got, want)
+		}
+	}
+	if got := (cliTool{path: "oc"}).display("oc get projects"); got != "oc get projects" {
+		t.Errorf("oc display() = %q, want the command unchanged", got)
+	}
+}
+
+func TestCLIToolUnsupportedCommand(t *testing.T) {
+	kubectl := cliTool{path: "kubectl", kubectl: true}
+	if reason := kubectl.unsupportedCommand("oc start-build web"); !strings.Contains(reason, "OpenShift-only") {
+		t.Errorf("unsupportedCommand(start-build) = %q, want an OpenShift-only reason", reason)
+	}
+	if reason := kubectl.unsupportedCommand("oc get pods"); reason != "" {
+		t.Errorf("unsupportedCommand(get pods) = %q, want none", reason)
+	}
+	if reason := (cliTool{path: "oc"}).unsupportedCommand("oc start-build web"); reason != "" {
+		t.Errorf("oc unsupportedCommand(start-build) = %q, want none", reason)
+	}
+}
@@ -0,0 +1,433 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Column is one column of a column set: a header and a query evaluated
+// against each resource, such as ".spec.nodeName".
+type Column struct {
+	Header string `json:"header"`
+	Path   string `json:"path"`
+}
+
+// ColumnSet is a named list of columns for one resource kind.
+type ColumnSet struct {
+	Name    string   `json:"name"`
+	Columns []Column `json:"columns"`
+}
+
+// conditionStatusPath returns the query for the status of a condition,
+// such as .status.conditions[?(@.type=="Ready")].status.
+func conditionStatusPath(conditionType string) string {
+	return `.status.conditions[?(@.type=="` + conditionType + `")].status`
+}
+
+// defaultColumnSets are the column sets shipped for the kinds of the main
+// menu. Sets in the config with the same name replace them.
+var defaultColumnSets = map[string][]ColumnSet{
+	"pods": {
+		{Name: "scheduling", Columns: []Column{
+			{"NODE", ".spec.nodeName"},
+			{"QOS", ".status.qosClass"},
+			{"RESTARTS", ".status.containerStatuses[*].restartCount"},
+			{"IMAGE", ".spec.containers[*].image"},
+		}},
+		{Name: "network", Columns: []Column{
+			{"IP", ".status.podIP"},
+			{"HOST IP", ".status.hostIP"},
+			{"PORTS", ".spec.containers[*].ports[*].containerPort"},
+			{"SERVICE ACCOUNT", ".spec.serviceAccountName"},
+		}},
+	},
+	"deployments": {
+		{Name: "rollout", Columns: []Column{
+			{"DESIRED", ".spec.replicas"},
+			{"READY", ".status.readyReplicas"},
+			{"UPDATED", ".status.updatedReplicas"},
+			{"STRATEGY", ".spec.strategy.type"},
+			{"IMAGE", ".spec.template.spec.containers[*].image"},
+		}},
+	},
+	"deploymentconfigs": {
+		{Name: "rollout", Columns: []Column{
+			{"DESIRED", ".spec.replicas"},
+			{"READY", ".status.readyReplicas"},
+			{"REVISION", ".status.latestVersion"},
+			{"TRIGGERS", ".spec.triggers[*].type"},
+		}},
+	},
+	"replicasets": {
+		{Name: "replicas", Columns: []Column{
+			{"DESIRED", ".spec.replicas"},
+			{"READY", ".status.readyReplicas"},
+			{"OWNER", ".metadata.ownerReferences[*].name"},
+		}},
+	},
+	"statefulsets": {
+		{Name: "replicas", Columns: []Column{
+			{"DESIRED", ".spec.replicas"},
+			{"READY", ".status.readyReplicas"},
+			{"SERVICE", ".spec.serviceName"},
+			{"IMAGE", ".spec.template.spec.containers[*].image"},
+		}},
+	},
+	"daemonsets": {
+		{Name: "scheduling", Columns: []Column{
+			{"DESIRED", ".status.desiredNumberScheduled"},
+			{"READY", ".status.numberReady"},
+			{"NODE SELECTOR", ".spec.template.spec.nodeSelector"},
+		}},
+	},
+	"jobs": {
+		{Name: "progress", Columns: []Column{
+			{"COMPLETIONS", ".spec.completions"},
+			{"SUCCEEDED", ".status.succeeded"},
+			{"FAILED", ".status.failed"},
+			{"STARTED", ".status.startTime"},
+		}},
+	},
+	"cronjobs": {
+		{Name: "schedule", Columns: []Column{
+			{"SCHEDULE", ".spec.schedule"},
+			{"SUSPEND", ".spec.suspend"},
+			{"LAST SCHEDULE", ".status.lastScheduleTime"},
+		}},
+	},
+	"services": {
+		{Name: "network", Columns: []Column{
+			{"TYPE", ".spec.type"},
+			{"CLUSTER IP", ".spec.clusterIP"},
+			{"PORTS", ".spec.ports[*].port"},
+			{"SELECTOR", ".spec.selector"},
+		}},
+	},
+	"routes": {
+		{Name: "network", Columns: []Column{
+			{"HOST", ".spec.host"},
+			{"SERVICE", ".spec.to.name"},
+			{"PORT", ".spec.port.targetPort"},
+			{"TLS", ".spec.tls.termination"},
+		}},
+	},
+	"ingresses": {
+		{Name: "network", Columns: []Column{
+			{"CLASS", ".spec.ingressClassName"},
+			{"HOSTS", ".spec.rules[*].host"},
+			{"ADDRESS", ".status.loadBalancer.ingress[*].ip"},
+		}},
+	},
+	"endpoints": {
+		{Name: "network", Columns: []Column{
+			{"ADDRESSES", ".subsets[*].addresses[*].ip"},
+			{"PORTS", ".subsets[*].ports[*].port"},
+		}},
+	},
+	"networkpolicies": {
+		{Name: "policy", Columns: []Column{
+			{"POD SELECTOR", ".spec.podSelector.matchLabels"},
+			{"POLICY TYPES", ".spec.policyTypes"},
+		}},
+	},
+	"persistentvolumes": {
+		{Name: "storage", Columns: []Column{
+			{"CAPACITY", ".spec.capacity.storage"},
+			{"ACCESS MODES", ".spec.accessModes"},
+			{"RECLAIM POLICY", ".spec.persistentVolumeReclaimPolicy"},
+			{"CLAIM", ".spec.claimRef.name"},
+			{"STORAGECLASS", ".spec.storageClassName"},
+		}},
+	},
+	"persistentvolumeclaims": {
+		{Name: "storage", Columns: []Column{
+			{"VOLUME", ".spec.volumeName"},
+			{"CAPACITY", ".status.capacity.storage"},
+			{"ACCESS MODES", ".spec.accessModes"},
+			{"STORAGECLASS", ".spec.storageClassName"},
+		}},
+	},
+	"storageclasses": {
+		{Name: "storage", Columns: []Column{
+			{"PROVISIONER", ".provisioner"},
+			{"RECLAIM POLICY", ".reclaimPolicy"},
+			{"BINDING MODE", ".volumeBindingMode"},
+			{"DEFAULT", ".metadata.annotations['storageclass.kubernetes.io/is-default-class']"},
+		}},
+	},
+	"volumesnapshots": {
+		{Name: "storage", Columns: []Column{
+			{"SOURCE PVC", ".spec.source.persistentVolumeClaimName"},
+			{"CLASS", ".spec.volumeSnapshotClassName"},
+			{"READY", ".status.readyToUse"},
+			{"SIZE", ".status.restoreSize"},
+		}},
+	},
+	"configmaps": {
+		{Name: "data", Columns: []Column{
+			{"KEYS", ".data | keys"},
+		}},
+	},
+	"secrets": {
+		{Name: "data", Columns: []Column{
+			{"TYPE", ".type"},
+			{"KEYS", ".data | keys"},
+		}},
+	},
+	"serviceaccounts": {
+		{Name: "secrets", Columns: []Column{
+			{"SECRETS", ".secrets[*].name"},
+			{"PULL SECRETS", ".imagePullSecrets[*].name"},
+		}},
+	},
+	"rolebindings": {
+		{Name: "subjects", Columns: []Column{
+			{"ROLE", ".roleRef.name"},
+			{"SUBJECT KINDS", ".subjects[*].kind"},
+			{"SUBJECTS", ".subjects[*].name"},
+		}},
+	},
+	"clusterrolebindings": {
+		{Name: "subjects", Columns: []Column{
+			{"ROLE", ".roleRef.name"},
+			{"SUBJECT KINDS", ".subjects[*].kind"},
+			{"SUBJECTS", ".subjects[*].name"},
+		}},
+	},
+	"events": {
+		{Name: "details", Columns: []Column{
+			{"TYPE", ".type"},
+			{"REASON", ".reason"},
+			{"OBJECT", ".involvedObject.name"},
+			{"COUNT", ".count"},
+			{"MESSAGE", ".message"},
+		}},
+	},
+	"nodes": {
+		{Name: "capacity", Columns: []Column{
+			{"READY", conditionStatusPath("Ready")},
+			{"INTERNAL IP", `.status.addresses[?(@.type=="InternalIP")].address`},
+			{"CPU", ".status.allocatable.cpu"},
+			{"MEMORY", ".status.allocatable.memory"},
+			{"VERSION", ".status.nodeInfo.kubeletVersion"},
+		}},
+		{Name: "system", Columns: []Column{
+			{"OS IMAGE", ".status.nodeInfo.osImage"},
+			{"KERNEL", ".status.nodeInfo.kernelVersion"},
+			{"RUNTIME", ".status.nodeInfo.containerRuntimeVersion"},
+		}},
+	},
+	"buildconfigs": {
+		{Name: "source", Columns: []Column{
+			{"STRATEGY", ".spec.strategy.type"},
+			{"SOURCE", ".spec.source.git.uri"},
+			{"OUTPUT", ".spec.output.to.name"},
+			{"LATEST", ".status.lastVersion"},
+		}},
+	},
+	"builds": {
+		{Name: "progress", Columns: []Column{
+			{"PHASE", ".status.phase"},
+			{"STRATEGY", ".spec.strategy.type"},
+			{"STARTED", ".status.startTimestamp"},
+			{"OUTPUT", ".status.outputDockerImageReference"},
+		}},
+	},
+	"imagestreams": {
+		{Name: "tags", Columns: []Column{
+			{"REPOSITORY", ".status.dockerImageRepository"},
+			{"TAGS", ".status.tags[*].tag"},
+		}},
+	},
+	"imagestreamtags": {
+		{Name: "image", Columns: []Column{
+			{"IMAGE", ".image.dockerImageReference"},
+			{"CREATED", ".image.metadata.creationTimestamp"},
+		}},
+	},
+	"templates": {
+		{Name: "contents", Columns: []Column{
+			{"PARAMETERS", ".parameters | length"},
+			{"OBJECTS", ".objects | length"},
+			{"DESCRIPTION", ".metadata.annotations.description"},
+		}},
+	},
+	"clusterversions": {
+		{Name: "update", Columns: []Column{
+			{"VERSION", ".status.desired.version"},
+			{"CHANNEL", ".spec.channel"},
+			{"AVAILABLE", conditionStatusPath("Available")},
+			{"PROGRESSING", conditionStatusPath("Progressing")},
+		}},
+	},
+	"clusteroperators": {
+		{Name: "conditions", Columns: []Column{
+			{"VERSION", `.status.versions[?(@.name=="operator")].version`},
+			{"AVAILABLE", conditionStatusPath("Available")},
+			{"PROGRESSING", conditionStatusPath("Progressing")},
+			{"DEGRADED", conditionStatusPath("Degraded")},
+		}},
+	},
+	"machineconfigpools": {
+		{Name: "machines", Columns: []Column{
+			{"CONFIG", ".status.configuration.name"},
+			{"UPDATED", conditionStatusPath("Updated")},
+			{"MACHINES", ".status.machineCount"},
+			{"READY", ".status.readyMachineCount"},
+			{"DEGRADED", ".status.degradedMachineCount"},
+		}},
+	},
+	"namespaces": {
+		{Name: "owner", Columns: []Column{
+			{"PHASE", ".status.phase"},
+			{"DISPLAY NAME", ".metadata.annotations['openshift.io/display-name']"},
+			{"REQUESTER", ".metadata.annotations['openshift.io/requester']"},
+		}},
+	},
+	"projects": {
+		{Name: "owner", Columns: []Column{
+			{"PHASE", ".status.phase"},
+			{"DISPLAY NAME", ".metadata.annotations['openshift.io/display-name']"},
+			{"REQUESTER", ".metadata.annotations['openshift.io/requester']"},
+		}},
+	},
+}
+
+// columnSets returns the column sets of kind: the defaults, replaced by or
+// followed by those in the config.
+func (nav *OCNavigator) columnSets(kind string) []ColumnSet {
+	sets := append([]ColumnSet(nil), defaultColumnSets[kind]...)
+	for _, custom := range nav.config.Columns[kind] {
+		replaced := false
+		for i := range sets {
+			if sets[i].Name == custom.Name {
+				sets[i], replaced = custom, true
+			}
+		}
+		if !replaced {
+			sets = append(sets, custom)
+		}
+	}
+	return sets
+}
+
+// columnValue evaluates a column against one object, joining several results
+// with commas as "oc get -o custom-columns" does.
+func columnValue(obj *docNode, query string) string {
+	nodes, err := evalQuery(obj, itemPath(query))
+	if err != nil {
+		return "<error>"
+	}
+	var values []string
+	for _, n := range nodes {
+		if n.container() || n.value != nil {
+			// Objects and lists are printed as JSON on a single line
+			values = append(values, strings.Join(strings.Fields(queryResultText(n)), " "))
+		}
+	}
+	if len(values) == 0 {
+		return "<none>"
+	}
+	return strings.Join(values, ",")
+}
+
+// columnKind returns the key column sets are stored under for the kind in
+// command: the plural resource name, such as "pods".
+func (nav *OCNavigator) columnKind(command string) string {
+	kind := resourceKind(command)
+	if kind == "" {
+		return ""
+	}
+	kind, _, _ = strings.Cut(strings.Split(kind, ",")[0], "/")
+	if nav.apis != nil {
+		if r, ok := nav.apis.resolve(kind); ok {
+			return r.Name
+		}
+	}
+	return normalizeResource(kind)
+}
+
+// itemPath turns a query over a whole list, such as .items[*].spec.nodeName,
+// into the query for a single item.
+func itemPath(query string) string {
+	query = strings.TrimSpace(query)
+	if strings.HasPrefix(query, "{") && strings.HasSuffix(query, "}") {
+		query = strings.TrimSpace(query[1 : len(query)-1])
+	}
+	query = strings.TrimPrefix(query, "$")
+	for _, prefix := range []string{".items[*]", ".items[]"} {
+		if strings.HasPrefix(query, prefix) {
+			query = strings.TrimPrefix(query, prefix)
+			query = strings.TrimPrefix(strings.TrimSpace(query), "|")
+			break
+		}
+	}
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return "."
+	}
+	return query
+}
+
+// listItems returns the items of a List document such as "oc get pods -o
+// json" prints, or nil when root is not a List.
+func listItems(root *docNode) []*docNode {
+	if root == nil || !root.object || !strings.HasSuffix(root.str("kind"), "List") {
+		return nil
+	}
+	if items := root.child("items"); items != nil && items.array {
+		return items.children
+	}
+	return nil
+}
+
+// resolvesOnAny reports whether a column query finds a value on at least one
+// of items, so that the column will not read <none> on every row.
+func resolvesOnAny(items []*docNode, query string) bool {
+	for _, item := range items {
+		if v := columnValue(item, query); v != "<none>" && v != "<error>" {
+			return true
+		}
+	}
+	return false
+}
+
+// columnHeader suggests a header for a query: its last field name.
+func columnHeader(query string) string {
+	fields := strings.FieldsFunc(query, func(r rune) bool {
+		return r == '.' || r == '[' || r == ']' || r == '{' || r == '}' || r == '|' || r == ' ' || r == '*' || r == '@'
+	})
+	for i := len(fields) - 1; i >= 0; i-- {
+		if name := strings.Trim(fields[i], `'"`); name != "" && !strings.ContainsAny(name, "0123456789()=!<>") {
+			return strings.ToUpper(name)
+		}
+	}
+	return "VALUE"
+}
+
+// addColumn appends a column to the named set of kind, creating the set in
+// the config when it is not there yet, and saves the config.
+func (nav *OCNavigator) addColumn(kind, set string, column Column) {
+	if nav.config.Columns == nil {
+		nav.config.Columns = make(map[string][]ColumnSet)
+	}
+	sets := nav.config.Columns[kind]
+	for i := range sets {
+		if sets[i].Name == set {
+			sets[i].Columns = append(sets[i].Columns, column)
+			nav.saveConfig()
+			nav.notify(notifySuccess, fmt.Sprintf("Added column %s to %s columns %q", column.Header, kind, set))
+			return
+		}
+	}
+	// Adding to a default set keeps its columns
+	columns := []Column{column}
+	for _, defaults := range defaultColumnSets[kind] {
+		if defaults.Name == set {
+			columns = append(append([]Column(nil), defaults.Columns...), column)
+		}
+	}
+	nav.config.Columns[kind] = append(sets, ColumnSet{Name: set, Columns: columns})
+	nav.saveConfig()
+	nav.notify(notifySuccess, fmt.Sprintf("Saved %s columns %q", kind, set))
+}
@@ -0,0 +1,101 @@
+package main
+
+import "testing"
+
+func TestItemPath(t *testing.T) {
+	tests := map[string]string{
+		".status.phase":                       ".status.phase",
+		"{.status.phase}":                     ".status.phase",
+		"{.items[*].status.phase}":            ".status.phase",
+		"$.items[*].metadata.name":            ".metadata.name",
+		".items[] | .spec.replicas":           ".spec.replicas",
+		".items[].spec.replicas":              ".spec.replicas",
+		".items[*]":                           ".",
+		"":                                    ".",
+		".items[0].metadata.name":             ".items[0].metadata.name",
+		" { .items[*].metadata.labels.app } ": ".metadata.labels.app",
+	}
+	for query, want := range tests {
+		if got := itemPath(query); got != want {
+			t.Errorf("itemPath(%q) = %q, want %q", query, got, want)
+		}
+	}
+}
+
+func TestColumnHeader(t *testing.T) {
+	tests := map[string]string{
+		".status.phase":                                 "PHASE",
+		"{.items[*].spec.nodeName}":                     "NODENAME",
+		".spec.containers[0].image":                     "IMAGE",
+		".spec.containers[*].image":                     "IMAGE",
+		`.metadata.labels['app']`:                       "APP",
+		".items[] | .spec.replicas":                     "REPLICAS",
+		`.status.conditions[?(@.type=="Ready")].status`: "STATUS",
+		".spec.containers | length":                     "LENGTH",
+		".":                                             "VALUE",
+		"[0]":                                           "VALUE",
+	}
+	for query, want := range tests {
+		if got := columnHeader(query); got != want {
+			t.Errorf("columnHeader(%q) = %q, want %q", query, got, want)
+		}
+	}
+}
+
+func TestColumnValue(t *testing.T) {
+	obj, err := parseDocument([]byte(`{"metadata": {"name": "web-1", "labels": {"app": "web"}},
+		"spec": {"containers": [{"image": "nginx:1.25"}, {"image": "envoy:1.30"}], "nodeName": null},
+		"status": {"conditions": [{"type": "Ready", "status": "True"}]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]string{
+		".metadata.name":                                "web-1",
+		"{.items[*].metadata.name}":                     "web-1",
+		".spec.containers[*].image":                     "nginx:1.25,envoy:1.30",
+		".metadata.labels":                              `{ "app": "web" }`,
+		".spec.nodeName":                                "<none>",
+		".spec.missing":                                 "<none>",
+		`.status.conditions[?(@.type=="Ready")].status`: "True",
+		".items[": "<error>",
+	}
+	for query, want := range tests {
+		if got := columnValue(obj, query); got != want {
+			t.Errorf("columnValue(%q) = %q, want %q", query, got, want)
+		}
+	}
+}
+
+func TestResolvesOnAny(t *testing.T) {
+	list, err := parseDocument([]byte(`{"apiVersion": "v1", "kind": "List", "items": [
+		{"metadata": {"name": "web-1"}, "spec": {"nodeName": "worker-1"}, "status": {"phase": "Running"}},
+		{"metadata": {"name": "web-2"}, "spec": {}, "status": {"phase": "Pending"}}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := listItems(list)
+	if len(items) != 2 {
+		t.Fatalf("listItems() = %d items, want 2", len(items))
+	}
+	tests := map[string]bool{
+		".items[*].spec.nodeName":                            true,
+		".items[].metadata.name":                             true,
+		".status.phase":                                      true,
+		`.items[?(@.status.phase=="Running")].spec.nodeName`: false,
+		".items[0].metadata.name":                            false,
+		".spec.missing":                                      false,
+	}
+	for query, want := range tests {
+		if got := resolvesOnAny(items, itemPath(query)); got != want {
+			t.Errorf("resolvesOnAny(%q) = %v, want %v", query, got, want)
+		}
+	}
+
+	pod, err := parseDocument([]byte(`{"apiVersion": "v1", "kind": "Pod", "metadata": {"name": "web-1"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := listItems(pod); got != nil {
+		t.Errorf("listItems(Pod) = %d items, want nil", len(got))
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Config holds the per-user settings persisted between sessions.
+type Config struct {
+	Favorites []Favorite        `json:"favorites,omitempty"`
+	Keys      map[string]string `json:"keys,omitempty"`
+	VimKeys   bool              `json:"vim_keys,omitempty"`
+	Layout    LayoutConfig      `json:"layout"`
+	Theme     string            `json:"theme,omitempty"`
+
+	// Permissions is "dim" (default), "hide" or "off" for menu items the
+	// current user is not allowed to run.
+	Permissions string `json:"permissions,omitempty"`
+
+	// MissingAPIs is "hide" (default) or "mark" for menu items whose API the
+	// cluster does not serve.
+	MissingAPIs string `json:"missing_apis,omitempty"`
+
+	// NotificationTimeouts maps a severity (info, success, warn, error) to
+	// how long its messages stay in the status bar, e.g. "5s"; "0s" keeps
+	// them until the next message.
+	NotificationTimeouts map[string]string `json:"notification_timeouts,omitempty"`
+
+	// Columns maps a resource such as "pods" to its named column sets.
+	Columns map[string][]ColumnSet `json:"columns,omitempty"`
+}
+
+// configPath returns the location of the user config file, honouring
+// OC_NAVIGATOR_CONFIG when it is set.
+func configPath() (string, error) {
+	if path := os.Getenv("OC_NAVIGATOR_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "oc-navigator", "config.json"), nil
+}
+
+// loadConfig reads the user config file. A missing file yields an empty config.
+func loadConfig() (*Config, error) {
+	config := &Config{}
+
+	path, err := configPath()
+	if err != nil {
+		return config, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return config, err
+	}
+
+	if err := json.Unmarshal(data, config); err != nil {
+		return &Config{}, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// menuFilePath returns the location of the optional menu file, which lives
+// next to the config file unless OC_NAVIGATOR_MENU is set.
+func menuFilePath() (string, error) {
+	if path := os.Getenv("OC_NAVIGATOR_MENU"); path != "" {
+		return path, nil
+	}
+	path, err := configPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "menu.json"), nil
+}
+
+// loadMenuFile reads a custom menu tree that replaces the built-in menu. It
+// returns nil when there is no menu file.
+func loadMenuFile() ([]*MenuItem, error) {
+	path, err := menuFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var menu []*MenuItem
+	if err := json.Unmarshal(data, &menu); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if len(menu) == 0 {
+		return nil, fmt.Errorf("%s defines no menu items", path)
+	}
+	return menu, nil
+}
+
+// save writes the config back to the user config file.
+func (c *Config) save() error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// saveConfig persists the config, refusing to overwrite a file that failed
+// to load so that a typo never wipes the user's settings.
+func (nav *OCNavigator) saveConfig() {
+	if nav.configErr != nil {
+		nav.notify(notifyError, fmt.Sprintf("Not saving settings, config failed to load: %v", nav.configErr))
+		return
+	}
+	if err := nav.config.save(); err != nil {
+		nav.notify(notifyError, fmt.Sprintf("Error saving settings: %v", err))
+	}
+}
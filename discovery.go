@@ -0,0 +1,282 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Display modes for menu items whose API the cluster does not serve, set with
+// "missing_apis" in the config.
+const (
+	missingAPIsHide = "hide"
+	missingAPIsMark = "mark"
+)
+
+// discoveryTimeout bounds "oc api-resources", which queries every API group.
+const discoveryTimeout = 30 * time.Second
+
+// apiResource is one row of "oc api-resources -o wide".
+type apiResource struct {
+	Name       string   `json:"name"`
+	ShortNames []string `json:"short_names,omitempty"`
+	Group      string   `json:"group,omitempty"`
+	Version    string   `json:"version"`
+	Namespaced bool     `json:"namespaced"`
+	Kind       string   `json:"kind"`
+	Verbs      []string `json:"verbs,omitempty"`
+}
+
+// apiCatalog holds the resources served by the cluster of one context.
+type apiCatalog struct {
+	Context   string        `json:"context"`
+	Fetched   time.Time     `json:"fetched"`
+	Resources []apiResource `json:"resources"`
+}
+
+var apiColumnPattern = regexp.MustCompile(`\S+`)
+
+// parseAPIResources reads "oc api-resources -o wide". Columns are located by
+// their header since SHORTNAMES and CATEGORIES are often empty.
+func parseAPIResources(output string) []apiResource {
+	lines := strings.Split(output, "\n")
+	if len(lines) == 0 {
+		return nil
+	}
+
+	columns := make(map[string]int)
+	var starts []int
+	for _, loc := range apiColumnPattern.FindAllStringIndex(lines[0], -1) {
+		columns[lines[0][loc[0]:loc[1]]] = len(starts)
+		starts = append(starts, loc[0])
+	}
+	for _, required := range []string{"NAME", "APIVERSION", "NAMESPACED", "KIND"} {
+		if _, ok := columns[required]; !ok {
+			return nil
+		}
+	}
+
+	field := func(line, name string) string {
+		i, ok := columns[name]
+		if !ok || starts[i] >= len(line) {
+			return ""
+		}
+		end := len(line)
+		if i+1 < len(starts) && starts[i+1] < end {
+			end = starts[i+1]
+		}
+		return strings.TrimSpace(line[starts[i]:end])
+	}
+
+	var resources []apiResource
+	for _, line := range lines[1:] {
+		name := field(line, "NAME")
+		if name == "" {
+			continue
+		}
+		group, version, found := strings.Cut(field(line, "APIVERSION"), "/")
+		if !found {
+			group, version = "", group
+		}
+		resource := apiResource{
+			Name:       name,
+			Group:      group,
+			Version:    version,
+			Namespaced: field(line, "NAMESPACED") == "true",
+			Kind:       field(line, "KIND"),
+			Verbs:      strings.FieldsFunc(strings.Trim(field(line, "VERBS"), "[]"), isVerbSeparator),
+		}
+		if short := field(line, "SHORTNAMES"); short != "" {
+			resource.ShortNames = strings.Split(short, ",")
+		}
+		resources = append(resources, resource)
+	}
+	return resources
+}
+
+// isVerbSeparator splits the VERBS column, written as "[get list]" by oc
+// and as "get,list" by some kubectl versions.
+func isVerbSeparator(r rune) bool {
+	return r == ' ' || r == ','
+}
+
+// resolve finds the resource for a kind as written on the command line, such
+// as "dc", "deployment", "Route" or "nodes.metrics.k8s.io".
+func (c *apiCatalog) resolve(kind string) (apiResource, bool) {
+	name, group, _ := strings.Cut(strings.ToLower(kind), ".")
+	for _, r := range c.Resources {
+		if group != "" && r.Group != group && !strings.HasPrefix(r.Group, group+".") {
+			continue
+		}
+		if r.Name == name || strings.ToLower(r.Kind) == name || r.Name == normalizeResource(name) {
+			return r, true
+		}
+		for _, short := range r.ShortNames {
+			if short == name {
+				return r, true
+			}
+		}
+	}
+	return apiResource{}, false
+}
+
+// sameResources reports whether other lists the same resources, so that a
+// fresh discovery matching the cache does not disturb the menu.
+func (c *apiCatalog) sameResources(other *apiCatalog) bool {
+	if other == nil || len(c.Resources) != len(other.Resources) {
+		return false
+	}
+	for i := range c.Resources {
+		a, b := c.Resources[i], other.Resources[i]
+		if a.Name != b.Name || a.Group != b.Group || a.Version != b.Version ||
+			a.Kind != b.Kind || a.Namespaced != b.Namespaced || strings.Join(a.Verbs, " ") != strings.Join(b.Verbs, " ") {
+			return false
+		}
+	}
+	return true
+}
+
+// requiredResource returns the resource an item needs: its Resource tag, or
+// the kind its command works on.
+func (item *MenuItem) requiredResource() string {
+	if item.Resource != "" {
+		return item.Resource
+	}
+	fields := strings.Fields(item.Command)
+	if len(fields) < 3 || fields[0] != "oc" || strings.HasPrefix(fields[2], "-") {
+		return ""
+	}
+	switch fields[1] {
+	case "get", "describe", "delete", "edit", "explain":
+		kind, _, _ := strings.Cut(strings.Split(fields[2], ",")[0], "/")
+		if kind == "all" {
+			return ""
+		}
+		return kind
+	case "logs":
+		return "pods"
+	}
+	return ""
+}
+
+// unsupported returns why the cluster cannot serve item, or an empty string
+// when it can or the API resources are not known yet.
+func (nav *OCNavigator) unsupported(item *MenuItem) string {
+	if reason := nav.cli.unsupportedCommand(item.Command); reason != "" {
+		return reason
+	}
+	if nav.apis == nil {
+		return ""
+	}
+	resource := nav.cli.requiredResource(item.requiredResource())
+	if resource == "" {
+		return ""
+	}
+	if _, ok := nav.apis.resolve(resource); ok {
+		return ""
+	}
+	return fmt.Sprintf("this cluster does not serve the %q API", resource)
+}
+
+// apiCachePath returns where the API resources of contextName are cached.
+func apiCachePath(contextName string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	name := strings.Map(func(r rune) rune {
+		if r == '/' || r == ':' || r == os.PathSeparator {
+			return '_'
+		}
+		return r
+	}, contextName)
+	return filepath.Join(dir, "oc-navigator", "api-resources", name+".json"), nil
+}
+
+// loadAPICatalog reads the cached catalog of contextName, if any.
+func loadAPICatalog(contextName string) *apiCatalog {
+	path, err := apiCachePath(contextName)
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var catalog apiCatalog
+	if json.Unmarshal(data, &catalog) != nil || catalog.Context != contextName {
+		return nil
+	}
+	return &catalog
+}
+
+// save writes the catalog to the cache.
+func (c *apiCatalog) save() error {
+	path, err := apiCachePath(c.Context)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// refreshCapabilities applies the cached API resources of the current context
+// right away and rediscovers them in the background.
+func (nav *OCNavigator) refreshCapabilities() {
+	contextName := nav.currentContext
+	if nav.apis == nil || nav.apis.Context != contextName {
+		nav.apis = loadAPICatalog(contextName)
+		nav.rebuildRootMenu()
+	}
+	nav.apisSeq++
+	seq := nav.apisSeq
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), discoveryTimeout)
+		defer cancel()
+		// api-resources fails when a single aggregated API is down, but still
+		// lists everything else, so the output is used whenever it parses.
+		output, _, err := runCommandContext(ctx, nav.cli, "oc api-resources -o wide")
+		var catalog *apiCatalog
+		var saveErr error
+		if resources := parseAPIResources(output); len(resources) > 0 {
+			catalog = &apiCatalog{Context: contextName, Fetched: time.Now(), Resources: resources}
+			saveErr = catalog.save()
+		}
+
+		nav.app.QueueUpdateDraw(func() {
+			if seq != nav.apisSeq {
+				return
+			}
+			if catalog == nil {
+				if err != nil {
+					nav.notify(notifyWarn, "Could not discover API resources: "+err.Error())
+				}
+				return
+			}
+			if saveErr != nil {
+				nav.notify(notifyWarn, "Could not cache API resources: "+saveErr.Error())
+			}
+			if catalog.sameResources(nav.apis) {
+				nav.apis = catalog
+				return
+			}
+			nav.apis = catalog
+			nav.rebuildRootMenu()
+			if index := nav.menuList.GetCurrentItem(); index >= 0 && index < len(nav.currentMenu) {
+				nav.showItemDetails(nav.currentMenu[index])
+			}
+		})
+	}()
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+const apiResourcesOutput = `NAME                 SHORTNAMES   APIVERSION                 NAMESPACED   KIND              VERBS                                        CATEGORIES
+configmaps           cm           v1                         true         ConfigMap         [create delete get list patch update watch]
+nodes                no           v1                         false        Node              create,delete,get,list,patch,update,watch
+deploymentconfigs    dc           apps.openshift.io/v1       true         DeploymentConfig  [create delete get list patch update watch]  all
+nodes                             metrics.k8s.io/v1beta1     false        NodeMetrics       [get list]
+routes                            route.openshift.io/v1      true         Route             [create delete get list patch update watch]  all
+`
+
+func TestParseAPIResources(t *testing.T) {
+	verbs := []string{"create", "delete", "get", "list", "patch", "update", "watch"}
+	want := []apiResource{
+		{Name: "configmaps", ShortNames: []string{"cm"}, Version: "v1", Namespaced: true, Kind: "ConfigMap", Verbs: verbs},
+		{Name: "nodes", ShortNames: []string{"no"}, Version: "v1", Kind: "Node", Verbs: verbs},
+		{Name: "deploymentconfigs", ShortNames: []string{"dc"}, Group: "apps.openshift.io", Version: "v1",
+			Namespaced: true, Kind: "DeploymentConfig", Verbs: verbs},
+		{Name: "nodes", Group: "metrics.k8s.io", Version: "v1beta1", Kind: "NodeMetrics", Verbs: []string{"get", "list"}},
+		{Name: "routes", Group: "route.openshift.io", Version: "v1", Namespaced: true, Kind: "Route", Verbs: verbs},
+	}
+	got := parseAPIResources(apiResourcesOutput)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseAPIResources() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	for _, output := range []string{"", "error: the server could not be reached\n", "NAME   KIND\npods   Pod\n"} {
+		if got := parseAPIResources(output); got != nil {
+			t.Errorf("parseAPIResources(%q) = %+v, want nil", output, got)
+		}
+	}
+}
+
+func TestAPICatalogResolve(t *testing.T) {
+	catalog := &apiCatalog{Resources: parseAPIResources(apiResourcesOutput)}
+	tests := []struct {
+		kind  string
+		group string
+		name  string
+		ok    bool
+	}{
+		{kind: "cm", name: "configmaps", ok: true},
+		{kind: "ConfigMap", name: "configmaps", ok: true},
+		{kind: "configmap", name: "configmaps", ok: true},
+		{kind: "dc", group: "apps.openshift.io", name: "deploymentconfigs", ok: true},
+		{kind: "Route", group: "route.openshift.io", name: "routes", ok: true},
+		{kind: "routes.route", group: "route.openshift.io", name: "routes", ok: true},
+		{kind: "nodes", name: "nodes", ok: true},
+		{kind: "nodes.metrics.k8s.io", group: "metrics.k8s.io", name: "nodes", ok: true},
+		{kind: "volumesnapshots", ok: false},
+	}
+	for _, tt := range tests {
+		r, ok := catalog.resolve(tt.kind)
+		if ok != tt.ok || r.Name != tt.name || r.Group != tt.group {
+			t.Errorf("resolve(%q) = %s/%s, %v, want %s/%s, %v", tt.kind, r.Group, r.Name, ok, tt.group, tt.name, tt.ok)
+		}
+	}
+}
+
+func TestSameResources(t *testing.T) {
+	catalog := &apiCatalog{Context: "a", Resources: parseAPIResources(apiResourcesOutput)}
+	same := &apiCatalog{Context: "b", Resources: parseAPIResources(apiResourcesOutput)}
+	if !catalog.sameResources(same) {
+		t.Error("sameResources() = false for the same resources")
+	}
+	if catalog.sameResources(nil) {
+		t.Error("sameResources(nil) = true")
+	}
+
+	changed := &apiCatalog{Resources: parseAPIResources(apiResourcesOutput)}
+	changed.Resources[1].Verbs = []string{"get"}
+	if catalog.sameResources(changed) {
+		t.Error("sameResources() = true with other verbs")
+	}
+	shorter := &apiCatalog{Resources: catalog.Resources[1:]}
+	if catalog.sameResources(shorter) {
+		t.Error("sameResources() = true with fewer resources")
+	}
+}
+
+func TestRequiredResource(t *testing.T) {
+	tests := []struct {
+		item *MenuItem
+		want string
+	}{
+		{&MenuItem{Command: "oc get pods"}, "pods"},
+		{&MenuItem{Command: "oc describe dc/web"}, "dc"},
+		{&MenuItem{Command: "oc get routes,svc"}, "routes"},
+		{&MenuItem{Command: "oc get virtualmachines", Resource: "virtualmachines.kubevirt.io"}, "virtualmachines.kubevirt.io"},
+		{&MenuItem{Command: "oc whoami"}, ""},
+		{&MenuItem{Command: "oc get -A pods"}, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.item.requiredResource(); got != tt.want {
+			t.Errorf("requiredResource(%q) = %q, want %q", tt.item.Command, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,382 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Formats a resource document can be shown in.
+const (
+	formatYAML = "yaml"
+	formatJSON = "json"
+)
+
+// docNode is one value of a JSON document. Objects keep their keys in the
+// order oc printed them, which encoding/json maps would lose.
+type docNode struct {
+	key      string // member name within an object parent
+	index    int    // position within an array parent, or -1
+	object   bool
+	array    bool
+	value    interface{} // scalars: string, json.Number, bool or nil
+	children []*docNode
+	parent   *docNode
+}
+
+// parseDocument reads a JSON document such as "oc get -o json" prints.
+func parseDocument(data []byte) (*docNode, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	root, err := decodeNode(dec)
+	if err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected data after the document")
+	}
+	return root, nil
+}
+
+func decodeNode(dec *json.Decoder) (*docNode, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return &docNode{index: -1, value: tok}, nil
+	}
+
+	n := &docNode{index: -1, object: delim == '{', array: delim == '['}
+	for dec.More() {
+		key := ""
+		if n.object {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key, _ = keyTok.(string)
+		}
+		child, err := decodeNode(dec)
+		if err != nil {
+			return nil, err
+		}
+		child.key, child.parent = key, n
+		if n.array {
+			child.index = len(n.children)
+		}
+		n.children = append(n.children, child)
+	}
+	// The closing delimiter
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// valueNode builds a document from a value decoded by encoding/json, such as
+// the objects of a watch stream. Members are sorted since maps keep no order.
+func valueNode(v interface{}) *docNode {
+	n := &docNode{index: -1}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		n.object = true
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			child := valueNode(v[key])
+			child.key, child.parent = key, n
+			n.children = append(n.children, child)
+		}
+	case []interface{}:
+		n.array = true
+		for i, item := range v {
+			child := valueNode(item)
+			child.index, child.parent = i, n
+			n.children = append(n.children, child)
+		}
+	case float64:
+		n.value = json.Number(strconv.FormatFloat(v, 'f', -1, 64))
+	default:
+		n.value = v
+	}
+	return n
+}
+
+// container reports whether the node is an object or an array.
+func (n *docNode) container() bool {
+	return n.object || n.array
+}
+
+// child returns the member key of an object node, or nil.
+func (n *docNode) child(key string) *docNode {
+	for _, c := range n.children {
+		if n.object && c.key == key {
+			return c
+		}
+	}
+	return nil
+}
+
+// str returns the value of a string member of an object node.
+func (n *docNode) str(key string) string {
+	if c := n.child(key); c != nil {
+		s, _ := c.value.(string)
+		return s
+	}
+	return ""
+}
+
+// isResource reports whether the node is a Kubernetes object.
+func (n *docNode) isResource() bool {
+	return n.object && n.str("kind") != "" && n.str("apiVersion") != ""
+}
+
+// isNoise reports whether the node is managedFields or the status of a
+// resource, which rarely matter when reading a manifest.
+func (n *docNode) isNoise() bool {
+	if n.parent == nil {
+		return false
+	}
+	return n.key == "managedFields" || n.key == "status" && n.parent.isResource()
+}
+
+var plainKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// jsonPath returns the path of the node from the document root in the
+// syntax of "oc get -o jsonpath", such as ".spec.containers[0].image".
+func (n *docNode) jsonPath() string {
+	var parts []string
+	for ; n.parent != nil; n = n.parent {
+		switch {
+		case n.parent.array:
+			parts = append(parts, fmt.Sprintf("[%d]", n.index))
+		case plainKeyPattern.MatchString(n.key):
+			parts = append(parts, "."+n.key)
+		default:
+			parts = append(parts, "['"+strings.ReplaceAll(n.key, "'", `\'`)+"']")
+		}
+	}
+	var path strings.Builder
+	for i := len(parts) - 1; i >= 0; i-- {
+		path.WriteString(parts[i])
+	}
+	return path.String()
+}
+
+// resource returns the closest Kubernetes object containing the node and the
+// path of the node within it, so that fields of a List item are found in the
+// schema of the item's kind.
+func (n *docNode) resource() (*docNode, []string) {
+	var fields []string
+	for ; n != nil; n = n.parent {
+		if n.isResource() {
+			return n, fields
+		}
+		if n.parent != nil && n.parent.object {
+			fields = append([]string{n.key}, fields...)
+		}
+	}
+	return nil, nil
+}
+
+// scalarText formats a scalar as JSON, which is also valid YAML when the
+// string does not need quoting.
+func (n *docNode) scalarText(format string) string {
+	switch v := n.value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		return v.String()
+	case string:
+		if format == formatYAML && plainYAML(v) {
+			return v
+		}
+		return strconv.Quote(v)
+	}
+	return fmt.Sprint(n.value)
+}
+
+var (
+	plainYAMLPattern   = regexp.MustCompile(`^[A-Za-z0-9_./][A-Za-z0-9 _./:@=+,()-]*$`)
+	yamlNumberPattern  = regexp.MustCompile(`^[-+]?(\.?[0-9]|0[xo])`)
+	yamlReservedValues = map[string]bool{"true": true, "false": true, "yes": true, "no": true,
+		"on": true, "off": true, "null": true, "y": true, "n": true, "~": true}
+)
+
+// plainYAML reports whether s can be written in YAML without quotes.
+func plainYAML(s string) bool {
+	return plainYAMLPattern.MatchString(s) && !strings.HasSuffix(s, " ") && !strings.Contains(s, ": ") &&
+		!yamlNumberPattern.MatchString(s) && !yamlReservedValues[strings.ToLower(s)]
+}
+
+// text renders the document as YAML or indented JSON, leaving out noise
+// when hideNoise is set.
+func (n *docNode) text(format string, hideNoise bool) string {
+	var b strings.Builder
+	if format == formatJSON {
+		n.writeJSON(&b, 0, hideNoise)
+		b.WriteString("\n")
+	} else {
+		n.writeYAML(&b, 0, hideNoise)
+	}
+	return b.String()
+}
+
+func (n *docNode) visibleChildren(hideNoise bool) []*docNode {
+	if !hideNoise {
+		return n.children
+	}
+	var visible []*docNode
+	for _, c := range n.children {
+		if !c.isNoise() {
+			visible = append(visible, c)
+		}
+	}
+	return visible
+}
+
+func (n *docNode) writeJSON(b *strings.Builder, depth int, hideNoise bool) {
+	if !n.container() {
+		b.WriteString(n.scalarText(formatJSON))
+		return
+	}
+	openDelim, closeDelim := "{", "}"
+	if n.array {
+		openDelim, closeDelim = "[", "]"
+	}
+	children := n.visibleChildren(hideNoise)
+	if len(children) == 0 {
+		b.WriteString(openDelim + closeDelim)
+		return
+	}
+	b.WriteString(openDelim + "\n")
+	indent := strings.Repeat("    ", depth+1)
+	for i, c := range children {
+		b.WriteString(indent)
+		if n.object {
+			b.WriteString(strconv.Quote(c.key) + ": ")
+		}
+		c.writeJSON(b, depth+1, hideNoise)
+		if i < len(children)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(strings.Repeat("    ", depth) + closeDelim)
+}
+
+func (n *docNode) writeYAML(b *strings.Builder, depth int, hideNoise bool) {
+	indent := strings.Repeat("  ", depth)
+	for _, c := range n.visibleChildren(hideNoise) {
+		prefix := indent + "- "
+		if n.object {
+			prefix = indent + yamlKey(c.key) + ":"
+		}
+		grandchildren := c.visibleChildren(hideNoise)
+		switch {
+		case !c.container():
+			if n.object {
+				prefix += " "
+			}
+			b.WriteString(prefix + c.yamlScalar(indent) + "\n")
+		case len(grandchildren) == 0 && c.object:
+			b.WriteString(strings.TrimSuffix(prefix, " ") + " {}\n")
+		case len(grandchildren) == 0:
+			b.WriteString(strings.TrimSuffix(prefix, " ") + " []\n")
+		case n.array && c.object:
+			// The first member shares the line of the dash
+			var nested strings.Builder
+			c.writeYAML(&nested, depth+1, hideNoise)
+			b.WriteString(prefix + strings.TrimPrefix(nested.String(), indent+"  "))
+		case n.object && c.array:
+			// Sequences in a mapping are not indented, as oc prints them
+			b.WriteString(prefix + "\n")
+			c.writeYAML(b, depth, hideNoise)
+		default:
+			b.WriteString(strings.TrimSuffix(prefix, " ") + "\n")
+			c.writeYAML(b, depth+1, hideNoise)
+		}
+	}
+}
+
+// yamlScalar formats a scalar, writing multi-line strings as literal blocks.
+// The block header keeps the trailing newlines and leading spaces of the
+// string; strings a block cannot hold exactly are quoted instead.
+func (n *docNode) yamlScalar(indent string) string {
+	s, ok := n.value.(string)
+	if !ok || !strings.Contains(s, "\n") {
+		return n.scalarText(formatYAML)
+	}
+	body := strings.TrimRight(s, "\n")
+	if strings.TrimSpace(body) == "" || strings.ContainsFunc(body, notLiteral) {
+		return strconv.Quote(s)
+	}
+
+	header := "|"
+	if strings.HasPrefix(body, " ") || strings.HasPrefix(body, "\n") {
+		// The indentation would otherwise be taken from the first line
+		header += "2"
+	}
+	switch len(s) - len(body) {
+	case 0:
+		header += "-"
+	case 1:
+	default:
+		header += "+"
+	}
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = indent + "  " + line
+		}
+	}
+	return header + "\n" + strings.Join(lines, "\n")
+}
+
+// notLiteral reports whether r cannot appear as itself in a literal block:
+// control characters other than tab are changed or rejected by parsers.
+func notLiteral(r rune) bool {
+	return r != '\t' && r != '\n' && (unicode.IsControl(r) || r == '\uFEFF')
+}
+
+func yamlKey(key string) string {
+	if plainYAML(key) {
+		return key
+	}
+	return strconv.Quote(key)
+}
+
+// outputFormatPattern matches the output option of an oc command.
+var outputFormatPattern = regexp.MustCompile(`(?:^|\s)(?:-o\s*|-o=|--output\s+|--output=)(yaml|json)(?:\s|$)`)
+
+// documentFormat returns "yaml" or "json" when command is an "oc get" that
+// prints whole resources in that format, or an empty string otherwise.
+func documentFormat(command string) string {
+	if !isListCommand(command) {
+		return ""
+	}
+	if m := outputFormatPattern.FindStringSubmatch(command); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// asJSON rewrites the output option of command to JSON, which can be parsed
+// without a YAML library and then shown in either format.
+func asJSON(command string) string {
+	return outputFormatPattern.ReplaceAllStringFunc(command, func(option string) string {
+		return strings.Replace(option, outputFormatPattern.FindStringSubmatch(option)[1], formatJSON, 1)
+	})
+}
@@ -0,0 +1,139 @@
+package main
+
+import "testing"
+
+func TestPlainYAML(t *testing.T) {
+	tests := map[string]bool{
+		"web-1":              true,
+		"nginx:1.25":         true,
+		"app=web,tier=front": true,
+		"/var/log":           true,
+		"":                   false,
+		"true":               false,
+		"No":                 false,
+		"null":               false,
+		"~":                  false,
+		"42":                 false,
+		"-1":                 false,
+		".5":                 false,
+		"0x1F":               false,
+		"key: value":         false,
+		"trailing ":          false,
+		"- item":             false,
+		"#comment":           false,
+		"*alias":             false,
+		"{json}":             false,
+		"multi\nline":        false,
+		"quote\"d":           false,
+	}
+	for s, want := range tests {
+		if got := plainYAML(s); got != want {
+			t.Errorf("plainYAML(%q) = %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestYAMLScalar(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{"plain", "web-1", "web-1"},
+		{"quoted", "true", `"true"`},
+		{"null", nil, "null"},
+		{"clip", "a\nb\n", "|\n    a\n    b"},
+		{"strip", "a\nb", "|-\n    a\n    b"},
+		{"keep", "a\n\n\n", "|+\n    a\n\n"},
+		{"blank line", "a\n\nb\n", "|\n    a\n\n    b"},
+		{"leading spaces", "  indented\nnext\n", "|2\n      indented\n    next"},
+		{"leading spaces strip", "  indented\nnext", "|2-\n      indented\n    next"},
+		{"leading newline", "\nafter\n", "|2\n\n    after"},
+		{"only newlines", "\n\n", `"\n\n"`},
+		{"control character", "bell\a\nring\n", `"bell\a\nring\n"`},
+		{"carriage return", "dos\r\nline\r\n", `"dos\r\nline\r\n"`},
+	}
+	for _, tt := range tests {
+		n := &docNode{index: -1, value: tt.value}
+		if got := n.yamlScalar("  "); got != tt.want {
+			t.Errorf("%s: yamlScalar() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWriteYAML(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string
+	}{
+		{
+			name: "mapping",
+			json: `{"kind":"Pod","metadata":{"name":"web-1","labels":{"app":"web"}}}`,
+			want: "kind: Pod\nmetadata:\n  name: web-1\n  labels:\n    app: web\n",
+		},
+		{
+			name: "sequence of mappings",
+			json: `{"containers":[{"name":"web","ports":[{"containerPort":8080}]},{"name":"envoy"}]}`,
+			want: "containers:\n- name: web\n  ports:\n  - containerPort: 8080\n- name: envoy\n",
+		},
+		{
+			name: "scalars and empty containers",
+			json: `{"args":["--v","2"],"ready":true,"ip":null,"env":[],"labels":{},"odd key":"x"}`,
+			want: "args:\n- \"--v\"\n- \"2\"\nready: true\nip: null\nenv: []\nlabels: {}\nodd key: x\n",
+		},
+		{
+			name: "block in a sequence",
+			json: `{"data":{"script":"#!/bin/sh\necho hi\n"},"list":["a\nb"]}`,
+			want: "data:\n  script: |\n    #!/bin/sh\n    echo hi\nlist:\n- |-\n  a\n  b\n",
+		},
+	}
+	for _, tt := range tests {
+		doc, err := parseDocument([]byte(tt.json))
+		if err != nil {
+			t.Fatalf("%s: parseDocument: %v", tt.name, err)
+		}
+		if got := doc.text(formatYAML, false); got != tt.want {
+			t.Errorf("%s: text(yaml) =\n%s\nwant\n%s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseDocument(t *testing.T) {
+	for _, data := range []string{`{"a":1}`, `[1,2]`, "  {\"a\": {\"b\": [true]}}\n"} {
+		if _, err := parseDocument([]byte(data)); err != nil {
+			t.Errorf("parseDocument(%q): %v", data, err)
+		}
+	}
+	for _, data := range []string{"", `{"a":`, `{"a":1} {"b":2}`, "Warning: deprecated\n{\"a\":1}"} {
+		if _, err := parseDocument([]byte(data)); err == nil {
+			t.Errorf("parseDocument(%q) succeeded, want an error", data)
+		}
+	}
+}
+
+func TestDocumentFormat(t *testing.T) {
+	tests := []struct {
+		command string
+		format  string
+		asJSON  string
+	}{
+		{"oc get pods -o yaml", formatYAML, "oc get pods -o json"},
+		{"oc get pod web-1 -oyaml", formatYAML, "oc get pod web-1 -ojson"},
+		{"oc get pod web-1 -o=yaml -n demo", formatYAML, "oc get pod web-1 -o=json -n demo"},
+		{"oc get deploy --output yaml", formatYAML, "oc get deploy --output json"},
+		{"oc get deploy --output=json", formatJSON, "oc get deploy --output=json"},
+		{"oc get pods -o wide", "", "oc get pods -o wide"},
+		{"oc get pods -o yamlish", "", "oc get pods -o yamlish"},
+		{"oc describe pod web-1 -o yaml", "", "oc describe pod web-1 -o json"},
+		{"oc get pods", "", "oc get pods"},
+	}
+	for _, tt := range tests {
+		if got := documentFormat(tt.command); got != tt.format {
+			t.Errorf("documentFormat(%q) = %q, want %q", tt.command, got, tt.format)
+		}
+		if got := asJSON(tt.command); got != tt.asJSON {
+			t.Errorf("asJSON(%q) = %q, want %q", tt.command, got, tt.asJSON)
+		}
+	}
+}
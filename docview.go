@@ -0,0 +1,220 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// documentView is a resource shown as a foldable tree in the output pane,
+// for "oc get" commands that print YAML or JSON.
+type documentView struct {
+	command   string
+	format    string
+	root      *docNode
+	hideNoise bool
+	collapsed map[*docNode]bool
+}
+
+// showDocument replaces the command output with a tree of root, in the
+// format the command asked for.
+func (nav *OCNavigator) showDocument(command, format string, root *docNode) {
+	nav.document = &documentView{
+		command:   command,
+		format:    format,
+		root:      root,
+		collapsed: make(map[*docNode]bool),
+	}
+	nav.renderDocument()
+	nav.outputPages.SwitchToPage("document")
+	nav.app.SetFocus(nav.documentTree)
+	nav.updateBreadcrumb()
+}
+
+// closeDocument returns the output pane to plain text.
+func (nav *OCNavigator) closeDocument() {
+	if nav.document == nil {
+		return
+	}
+	nav.document = nil
+	nav.outputPages.SwitchToPage("text")
+	if nav.app.GetFocus() == nav.documentTree {
+		nav.app.SetFocus(nav.menuList)
+	}
+	nav.updateBreadcrumb()
+}
+
+// renderDocument rebuilds the tree, keeping the folds and the selected field.
+func (nav *OCNavigator) renderDocument() {
+	doc := nav.document
+	var selected *docNode
+	if current := nav.documentTree.GetCurrentNode(); current != nil {
+		selected, _ = current.GetReference().(*docNode)
+	}
+
+	var selectedNode *tview.TreeNode
+	var add func(parent *tview.TreeNode, n *docNode)
+	add = func(parent *tview.TreeNode, n *docNode) {
+		for _, c := range n.visibleChildren(doc.hideNoise) {
+			expanded := !doc.collapsed[c]
+			node := tview.NewTreeNode(nav.documentLabel(c, expanded)).
+				SetReference(c).
+				SetExpanded(expanded)
+			parent.AddChild(node)
+			if c == selected {
+				selectedNode = node
+			}
+			add(node, c)
+		}
+	}
+	root := tview.NewTreeNode("").SetReference(doc.root).SetSelectable(false)
+	add(root, doc.root)
+
+	nav.documentTree.SetRoot(root).SetTopLevel(1)
+	if selectedNode == nil && len(root.GetChildren()) > 0 {
+		selectedNode = root.GetChildren()[0]
+	}
+	nav.documentTree.SetCurrentNode(selectedNode)
+	nav.updateDocumentTitle()
+}
+
+// documentLabel renders one line of the tree: the key or list dash, and the
+// value for scalars or a fold marker for mappings and lists.
+func (nav *OCNavigator) documentLabel(n *docNode, expanded bool) string {
+	var label strings.Builder
+	empty := len(n.visibleChildren(nav.document.hideNoise)) == 0
+	switch {
+	case !n.container() || empty:
+		label.WriteString("  ")
+	case expanded:
+		label.WriteString("▾ ")
+	default:
+		label.WriteString("▸ ")
+	}
+
+	json := nav.document.format == formatJSON
+	switch {
+	case n.parent.object && json:
+		label.WriteString(nav.theme.paint(roleKey, tview.Escape(strconv.Quote(n.key))) + ": ")
+	case n.parent.object:
+		label.WriteString(nav.theme.paint(roleKey, tview.Escape(yamlKey(n.key))) + ": ")
+	case !json:
+		label.WriteString(nav.theme.paint(roleMuted, "- "))
+	}
+
+	if !n.container() {
+		label.WriteString(nav.paintScalar(n))
+		return label.String()
+	}
+
+	open, closing, folded := "{", "}", "{…}"
+	if n.array {
+		open, closing, folded = "[", "]", "[…]"
+	}
+	if empty {
+		label.WriteString(nav.theme.paint(roleMuted, tview.Escape(open+closing)))
+		return label.String()
+	}
+	if expanded {
+		if json {
+			label.WriteString(nav.theme.paint(roleMuted, open))
+		} else if n.parent.array {
+			label.WriteString(nav.theme.paint(roleMuted, fmt.Sprintf("#%d", n.index)))
+		}
+	} else {
+		label.WriteString(nav.theme.paint(roleMuted, fmt.Sprintf("%s %d", folded, len(n.visibleChildren(nav.document.hideNoise)))))
+	}
+	// List entries are easier to tell apart by name
+	if name := n.str("name"); n.parent.array && name != "" {
+		label.WriteString(" " + nav.theme.paint(roleMuted, "name: "+tview.Escape(name)))
+	}
+	return label.String()
+}
+
+// paintScalar colors a scalar by its type.
+func (nav *OCNavigator) paintScalar(n *docNode) string {
+	text := n.scalarText(nav.document.format)
+	if s, ok := n.value.(string); ok && strings.Contains(s, "\n") {
+		// Multi-line strings are quoted to stay on one line of the tree
+		text = strconv.Quote(s)
+	}
+	role := roleString
+	switch n.value.(type) {
+	case bool, nil:
+		role = roleBoolean
+	case fmt.Stringer:
+		role = roleNumber
+	}
+	return nav.theme.paint(role, tview.Escape(text))
+}
+
+// updateDocumentTitle shows the JSONPath of the selected field in the title.
+func (nav *OCNavigator) updateDocumentTitle() {
+	doc := nav.document
+	title := fmt.Sprintf(" %s", strings.ToUpper(doc.format))
+	if node := nav.documentTree.GetCurrentNode(); node != nil {
+		if n, ok := node.GetReference().(*docNode); ok {
+			title += " " + tview.Escape("{"+n.jsonPath()+"}")
+		}
+	}
+	if doc.hideNoise {
+		title += " (managedFields and status hidden)"
+	}
+	nav.documentTree.SetTitle(title + " ")
+}
+
+// selectedDocNode returns the field under the cursor of the document tree.
+func (nav *OCNavigator) selectedDocNode() *docNode {
+	if nav.document == nil {
+		return nil
+	}
+	if node := nav.documentTree.GetCurrentNode(); node != nil {
+		n, _ := node.GetReference().(*docNode)
+		return n
+	}
+	return nil
+}
+
+// handleDocumentKeys folds with Enter, hides noise with m and copies the
+// JSONPath of the selected field with y.
+func (nav *OCNavigator) handleDocumentKeys(event *tcell.EventKey) *tcell.EventKey {
+	if nav.document == nil || event.Key() != tcell.KeyRune {
+		return event
+	}
+	switch event.Rune() {
+	case 'm':
+		nav.document.hideNoise = !nav.document.hideNoise
+		nav.renderDocument()
+		return nil
+	case 'y':
+		if n := nav.selectedDocNode(); n != nil {
+			path := "{" + n.jsonPath() + "}"
+			nav.copyToClipboard(path)
+			nav.notify(notifySuccess, "Copied "+path)
+		}
+		return nil
+	}
+	return event
+}
+
+// toggleDocumentFold folds or unfolds the selected mapping or list.
+func (nav *OCNavigator) toggleDocumentFold(node *tview.TreeNode) {
+	n, ok := node.GetReference().(*docNode)
+	if !ok || !n.container() {
+		return
+	}
+	node.SetExpanded(!node.IsExpanded())
+	nav.document.collapsed[n] = !node.IsExpanded()
+	node.SetText(nav.documentLabel(n, node.IsExpanded()))
+}
+
+// copyToClipboard puts text on the system clipboard with the terminal's
+// OSC 52 escape sequence, which works over SSH too.
+func (nav *OCNavigator) copyToClipboard(text string) {
+	if nav.screen != nil {
+		nav.screen.SetClipboard([]byte(text))
+	}
+}
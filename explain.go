@@ -0,0 +1,329 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// explainLookupDelay is how long the selection must rest on a field before
+// its description is looked up, so that scrolling through the tree or typing
+// a search does not start an "oc explain" for every field passed over.
+const explainLookupDelay = 200 * time.Millisecond
+
+// schemaField is one field of a resource schema from "oc explain --recursive".
+type schemaField struct {
+	name     string
+	path     string // dotted path below the kind, such as "spec.template.spec"
+	typ      string
+	required bool
+	children []*schemaField
+}
+
+// label is how the field is shown in the tree: its name and type, marked
+// when it is required and when it can be expanded.
+func (f *schemaField) label(expanded bool) string {
+	text := f.name + "  <" + f.typ + ">"
+	if f.required {
+		text += " *"
+	}
+	switch {
+	case len(f.children) == 0:
+		return "  " + text
+	case expanded:
+		return "▾ " + text
+	}
+	return "▸ " + text
+}
+
+// explainFieldPattern matches a field line of "oc explain --recursive". Older
+// clients indent by three spaces and separate the type with a tab, newer ones
+// indent by two and mark required fields.
+var explainFieldPattern = regexp.MustCompile(`^(\s+)([^\s<]+)\s+<([^>]*)>(\s+-required-)?\s*$`)
+
+// parseExplainRecursive reads the FIELDS section of "oc explain --recursive"
+// into a tree, using the indentation of each line to find its parent.
+func parseExplainRecursive(output string) []*schemaField {
+	type open struct {
+		indent int
+		field  *schemaField
+	}
+	var roots []*schemaField
+	var stack []open
+	inFields := false
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "FIELDS:") {
+			inFields = true
+			continue
+		}
+		if !inFields {
+			continue
+		}
+		m := explainFieldPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		indent := len(strings.ReplaceAll(m[1], "\t", "    "))
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		field := &schemaField{name: m[2], path: m[2], typ: m[3], required: m[4] != ""}
+		if len(stack) == 0 {
+			roots = append(roots, field)
+		} else {
+			parent := stack[len(stack)-1].field
+			field.path = parent.path + "." + field.name
+			parent.children = append(parent.children, field)
+		}
+		stack = append(stack, open{indent, field})
+	}
+	return roots
+}
+
+// explainTarget returns the resource name and, for kinds outside the core
+// group, the --api-version flag to pass to "oc explain" for kind. A dotted
+// field path cannot be appended to a group-qualified kind, so the group is
+// passed separately.
+func (nav *OCNavigator) explainTarget(kind string) (string, string) {
+	if nav.apis != nil {
+		if r, ok := nav.apis.resolve(kind); ok {
+			if r.Group == "" {
+				return r.Name, ""
+			}
+			return r.Name, " --api-version=" + r.groupVersion()
+		}
+	}
+	name, _, _ := strings.Cut(kind, ".")
+	return name, ""
+}
+
+// explainKind returns the kind the explain browser opens for: the one in the
+// live table, the last command or the selected menu item.
+func (nav *OCNavigator) explainKind() string {
+	if nav.live != nil {
+		if kind := resourceKind(nav.live.command); kind != "" {
+			return kind
+		}
+	}
+	var commands []string
+	if n := len(nav.commandHistory); n > 0 {
+		commands = append(commands, nav.commandHistory[n-1])
+	}
+	if index := nav.menuList.GetCurrentItem(); index >= 0 && index < len(nav.currentMenu) {
+		commands = append(commands, nav.currentMenu[index].Command)
+	}
+	for _, command := range commands {
+		if kind := resourceKind(command); kind != "" {
+			kind, _, _ = strings.Cut(strings.Split(kind, ",")[0], "/")
+			if kind != "all" {
+				return kind
+			}
+		}
+	}
+	return ""
+}
+
+// explainCurrentKind opens the explain browser for the kind on screen, asking
+// for one when nothing on screen names a kind. In a YAML or JSON document it
+// opens at the selected field.
+func (nav *OCNavigator) explainCurrentKind() {
+	if n := nav.selectedDocNode(); n != nil && nav.app.GetFocus() == nav.documentTree {
+		if resource, fields := n.resource(); resource != nil {
+			nav.showExplainBrowser(strings.ToLower(resource.str("kind")), strings.Join(fields, "."))
+			return
+		}
+	}
+	if kind := nav.explainKind(); kind != "" {
+		nav.showExplainBrowser(kind, "")
+		return
+	}
+	nav.prompt("Explain kind: ", "", nil, func(text string, accepted bool) {
+		if kind := strings.TrimSpace(text); accepted && kind != "" {
+			nav.showExplainBrowser(kind, "")
+		}
+	})
+}
+
+// showExplainBrowser shows the schema of kind as a tree. Each field shows its
+// type and whether it is required, and its description is looked up when it
+// is selected. The search box filters the tree by field path and starts out
+// with query.
+func (nav *OCNavigator) showExplainBrowser(kind, query string) {
+	name, apiVersion := nav.explainTarget(kind)
+
+	search := tview.NewInputField().SetLabel("Search: ").SetText(query).SetFieldBackgroundColor(tcell.ColorDefault)
+	tree := tview.NewTreeView()
+	details := tview.NewTextView().SetDynamicColors(true).SetWrap(true).SetWordWrap(true)
+	details.SetBorder(true).SetTitle(" Field ")
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(search, 1, 0, false).
+		AddItem(tview.NewFlex().
+			AddItem(tree, 0, 1, true).
+			AddItem(details, 0, 1, false), 0, 1, true)
+	layout.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Schema: %s (/ search, Enter expand, Esc close) ", name)).
+		SetTitleAlign(tview.AlignLeft)
+
+	var fields []*schemaField
+	descriptions := make(map[string]string)
+	// cancelLookup stops the description lookup in flight, if any
+	cancelLookup := func() {}
+	closeBrowser := func() {
+		cancelLookup()
+		nav.closeOverlay()
+	}
+
+	var showField func(field *schemaField)
+	showField = func(field *schemaField) {
+		cancelLookup()
+		details.Clear()
+		if field == nil {
+			return
+		}
+		fmt.Fprintf(details, "%s\n\n", nav.theme.paint(roleTitle, tview.Escape(field.path)))
+		fmt.Fprintf(details, "%s %s\n", nav.theme.paint(roleKey, "Type:"), tview.Escape(field.typ))
+		required := "no"
+		if field.required {
+			required = nav.theme.paint(roleWarning, "yes")
+		}
+		fmt.Fprintf(details, "%s %s\n\n", nav.theme.paint(roleKey, "Required:"), required)
+
+		if description, ok := descriptions[field.path]; ok {
+			fmt.Fprintf(details, "%s", tview.Escape(description))
+			return
+		}
+		fmt.Fprintf(details, "%s", nav.theme.paint(roleMuted, "loading..."))
+		ctx, cancel := context.WithCancel(context.Background())
+		cancelLookup = cancel
+		go func() {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(explainLookupDelay):
+			}
+			output, _, err := runCommandContext(ctx, nav.cli, "oc explain "+name+"."+field.path+apiVersion)
+			if ctx.Err() != nil {
+				return
+			}
+			description := explainText(output)
+			if err != nil {
+				description = fmt.Sprintf("not available (%v)", err)
+			}
+			nav.app.QueueUpdateDraw(func() {
+				// Another field was selected while the result was queued
+				if ctx.Err() != nil {
+					return
+				}
+				cancel()
+				// A failure is kept as well, or showing it would look it up again
+				descriptions[field.path] = description
+				if node := tree.GetCurrentNode(); node != nil && node.GetReference() == field {
+					showField(field)
+				}
+			})
+		}()
+	}
+
+	// build fills the tree with the fields whose path contains query, along
+	// with their parents. A query expands every match.
+	build := func(query string) {
+		root := tview.NewTreeNode(name).SetSelectable(false)
+		var add func(parent *tview.TreeNode, fields []*schemaField, query string) bool
+		add = func(parent *tview.TreeNode, fields []*schemaField, query string) bool {
+			found := false
+			for _, field := range fields {
+				node := tview.NewTreeNode(field.label(query != "")).SetReference(field).SetExpanded(query != "")
+				if field.required {
+					node.SetTextStyle(nav.theme.style(roleWarning))
+				}
+				if query == "" || strings.Contains(strings.ToLower(field.path), query) {
+					// A match keeps all of its fields, collapsed
+					add(node, field.children, "")
+				} else if !add(node, field.children, query) {
+					continue
+				}
+				parent.AddChild(node)
+				found = true
+			}
+			return found
+		}
+		add(root, fields, query)
+		tree.SetRoot(root).SetTopLevel(1)
+		// Select the field the query names exactly, or else the first one
+		current := root.GetChildren()
+		root.Walk(func(node, parent *tview.TreeNode) bool {
+			if field, ok := node.GetReference().(*schemaField); ok && strings.ToLower(field.path) == query {
+				current = []*tview.TreeNode{node}
+			}
+			return true
+		})
+		if len(current) > 0 {
+			tree.SetCurrentNode(current[0])
+			showField(current[0].GetReference().(*schemaField))
+		} else {
+			tree.SetCurrentNode(nil)
+			showField(nil)
+		}
+	}
+
+	tree.SetChangedFunc(func(node *tview.TreeNode) {
+		if field, ok := node.GetReference().(*schemaField); ok {
+			showField(field)
+		}
+	})
+	tree.SetSelectedFunc(func(node *tview.TreeNode) {
+		node.SetExpanded(!node.IsExpanded())
+		if field, ok := node.GetReference().(*schemaField); ok {
+			node.SetText(field.label(node.IsExpanded()))
+		}
+	})
+	tree.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape:
+			closeBrowser()
+			return nil
+		case event.Key() == tcell.KeyRune && event.Rune() == '/':
+			nav.app.SetFocus(search)
+			return nil
+		}
+		return event
+	})
+	search.SetChangedFunc(func(text string) {
+		build(strings.ToLower(strings.TrimSpace(text)))
+	})
+	search.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEscape:
+			closeBrowser()
+		case tcell.KeyEnter, tcell.KeyTab, tcell.KeyDown:
+			nav.app.SetFocus(tree)
+		}
+	})
+
+	tree.SetRoot(tview.NewTreeNode("loading " + name + "...").SetSelectable(false))
+	nav.showOverlay(layout)
+	nav.app.SetFocus(tree)
+
+	go func() {
+		output, _, err := runCommand(nav.cli, "oc explain "+name+" --recursive"+apiVersion)
+		nav.app.QueueUpdateDraw(func() {
+			if nav.overlay != layout {
+				return
+			}
+			if err != nil {
+				nav.closeOverlay()
+				nav.notify(notifyError, fmt.Sprintf("Cannot explain %s: %v", name, err))
+				return
+			}
+			fields = parseExplainRecursive(output)
+			build(strings.ToLower(strings.TrimSpace(search.GetText())))
+		})
+	}()
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// flattenSchema lists the fields of a schema tree depth first as
+// "path <type>", with a trailing " *" for required fields.
+func flattenSchema(fields []*schemaField) []string {
+	var flat []string
+	for _, field := range fields {
+		entry := field.path + " <" + field.typ + ">"
+		if field.required {
+			entry += " *"
+		}
+		flat = append(flat, entry)
+		flat = append(flat, flattenSchema(field.children)...)
+	}
+	return flat
+}
+
+func TestParseExplainRecursive(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []string
+	}{
+		{
+			name: "current client",
+			output: "KIND:       Pod\nVERSION:    v1\n\nDESCRIPTION:\n    Pod is a collection of containers.\n\n" +
+				"FIELDS:\n  apiVersion\t<string>\n  spec\t<PodSpec>\n    containers\t<[]Container> -required-\n" +
+				"      name\t<string> -required-\n      ports\t<[]ContainerPort>\n        containerPort\t<integer> -required-\n" +
+				"    nodeName\t<string>\n  status\t<PodStatus>\n",
+			want: []string{
+				"apiVersion <string>",
+				"spec <PodSpec>",
+				"spec.containers <[]Container> *",
+				"spec.containers.name <string> *",
+				"spec.containers.ports <[]ContainerPort>",
+				"spec.containers.ports.containerPort <integer> *",
+				"spec.nodeName <string>",
+				"status <PodStatus>",
+			},
+		},
+		{
+			name: "older client",
+			output: "KIND:     Pod\nVERSION:  v1\n\nRESOURCE: spec <Object>\n\nFIELDS:\n" +
+				"   apiVersion\t<string>\n   spec\t<Object>\n      containers\t<[]Object>\n         name\t<string>\n" +
+				"   status\t<Object>\n",
+			want: []string{
+				"apiVersion <string>",
+				"spec <Object>",
+				"spec.containers <[]Object>",
+				"spec.containers.name <string>",
+				"status <Object>",
+			},
+		},
+		{
+			name:   "fields before the section are ignored",
+			output: "DESCRIPTION:\n  name\t<string>\n",
+		},
+		{
+			name:   "error output",
+			output: `error: the server doesn't have a resource type "pdos"`,
+		},
+	}
+	for _, tt := range tests {
+		got := flattenSchema(parseExplainRecursive(tt.output))
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: parseExplainRecursive() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSchemaFieldLabel(t *testing.T) {
+	leaf := &schemaField{name: "name", typ: "string", required: true}
+	parent := &schemaField{name: "spec", typ: "PodSpec", children: []*schemaField{leaf}}
+	tests := []struct {
+		field    *schemaField
+		expanded bool
+		want     string
+	}{
+		{leaf, false, "  name  <string> *"},
+		{parent, false, "▸ spec  <PodSpec>"},
+		{parent, true, "▾ spec  <PodSpec>"},
+	}
+	for _, tt := range tests {
+		if got := tt.field.label(tt.expanded); got != tt.want {
+			t.Errorf("label(%s, %v) = %q, want %q", tt.field.name, tt.expanded, got, tt.want)
+		}
+	}
+}
+
+func TestExplainText(t *testing.T) {
+	output := "KIND:       Pod\nVERSION:    v1\n\nFIELD: spec.nodeName <string>\n\nDESCRIPTION:\n" +
+		"    NodeName is a request to schedule this pod onto a specific node.\n    If it is non-empty, the scheduler ignores it.\n\nFIELDS:\n  x\t<string>\n"
+	want := "NodeName is a request to schedule this pod onto a specific node. If it is non-empty, the scheduler ignores it."
+	if got := explainText(output); got != want {
+		t.Errorf("explainText() = %q, want %q", got, want)
+	}
+	if got := explainText("error: field not found"); got != "" {
+		t.Errorf("explainText(error) = %q, want empty", got)
+	}
+}
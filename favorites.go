@@ -0,0 +1,336 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const favoritesMenuName = "Favorites"
+
+// Favorite is a starred menu item or command listed in the Favorites menu.
+// Menu items are stored by their path so that they keep their submenu and
+// special handling; custom and history commands are stored as is.
+type Favorite struct {
+	Name    string `json:"name"`
+	Path    string `json:"path,omitempty"`
+	Command string `json:"command,omitempty"`
+	Context string `json:"context,omitempty"` // only shown on this context when set
+}
+
+// favoriteItems resolves the favorites that apply to the current context into
+// menu items, returning alongside each the index into config.Favorites.
+func (nav *OCNavigator) favoriteItems() ([]*MenuItem, []int) {
+	var items []*MenuItem
+	var indices []int
+
+	for i, fav := range nav.config.Favorites {
+		if fav.Context != "" && fav.Context != nav.currentContext {
+			continue
+		}
+
+		var item MenuItem
+		if fav.Path != "" {
+			_, target, err := resolveMenuPath(nav.baseMenu, fav.Path)
+			if err != nil {
+				// Keep stale entries in the config but do not show them
+				continue
+			}
+			item = *target
+		} else {
+			item = MenuItem{Name: fav.Name, Command: fav.Command, Description: "Favorite command", IsExec: true}
+		}
+		if fav.Context != "" {
+			item.Description += fmt.Sprintf(" (only on %s)", fav.Context)
+		}
+
+		items = append(items, &item)
+		indices = append(indices, i)
+	}
+	return items, indices
+}
+
+// rebuildRootMenu recomputes the Favorites section at the top of the main
+// menu and refreshes whichever menu level is currently displayed.
+func (nav *OCNavigator) rebuildRootMenu() {
+	openNames := make([]string, len(nav.menuParents))
+	for i, parent := range nav.menuParents {
+		openNames[i] = parent.Name
+	}
+	cursor := nav.menuList.GetCurrentItem()
+
+	items, indices := nav.favoriteItems()
+	nav.favoriteIndices = indices
+	nav.favoritesMenu = nil
+	nav.menuOrigins = make(map[*MenuItem]*MenuItem)
+	nav.rootMenu = nav.visibleMenu(nav.baseMenu)
+	if browse := nav.apiResourcesMenu(); browse != nil {
+		// After the last section, ahead of commands such as Custom Commands
+		at := len(nav.rootMenu)
+		for at > 0 && nav.rootMenu[at-1].Submenu == nil {
+			at--
+		}
+		generated := append(nav.visibleMenu([]*MenuItem{browse}), nav.rootMenu[at:]...)
+		nav.rootMenu = append(nav.rootMenu[:at:at], generated...)
+	}
+	if len(items) > 0 {
+		nav.favoritesMenu = &MenuItem{
+			Name:        favoritesMenuName,
+			Description: "Starred menu items and commands",
+			Submenu:     items,
+		}
+		nav.rootMenu = append([]*MenuItem{nav.favoritesMenu}, nav.rootMenu...)
+	}
+
+	// Reopen the same submenus in the new menu, or go back to the main menu
+	// when one of them is gone
+	menu := nav.rootMenu
+	parents := make([]*MenuItem, 0, len(openNames))
+	for _, name := range openNames {
+		parent := findSubmenu(menu, name)
+		if parent == nil {
+			nav.jumpToLevel(0)
+			nav.currentMenu = nav.rootMenu
+			nav.populateMenu()
+			return
+		}
+		parents = append(parents, parent)
+		menu = parent.Submenu
+	}
+	if len(parents) > 0 {
+		nav.menuStack[0] = nav.rootMenu
+		for i, parent := range parents {
+			nav.menuParents[i] = parent
+			if i+1 < len(parents) {
+				nav.menuStack[i+1] = parent.Submenu
+			}
+		}
+	}
+	nav.currentMenu = menu
+	nav.populateMenu()
+	nav.menuList.SetCurrentItem(min(cursor, len(menu)-1))
+}
+
+// findSubmenu returns the item of menu named name that opens a submenu.
+func findSubmenu(menu []*MenuItem, name string) *MenuItem {
+	for _, item := range menu {
+		if item.Name == name && item.Submenu != nil {
+			return item
+		}
+	}
+	return nil
+}
+
+// inFavoritesMenu reports whether the Favorites submenu is being displayed.
+func (nav *OCNavigator) inFavoritesMenu() bool {
+	return nav.favoritesMenu != nil && len(nav.menuParents) == 1 && nav.menuParents[0] == nav.favoritesMenu
+}
+
+// isFavorite reports whether item has been starred from the regular menus.
+func (nav *OCNavigator) isFavorite(item *MenuItem) bool {
+	path := menuItemPath(nav.baseMenu, nav.originalItem(item))
+	for _, fav := range nav.config.Favorites {
+		if path != "" && fav.Path == path {
+			return true
+		}
+		if path == "" && fav.Path == "" && item.Command != "" && fav.Command == item.Command {
+			return true
+		}
+	}
+	return false
+}
+
+// toggleFavorite stars or unstars the selected menu item. Inside the
+// Favorites menu it removes the selected entry.
+func (nav *OCNavigator) toggleFavorite() {
+	index := nav.menuList.GetCurrentItem()
+	if index < 0 || index >= len(nav.currentMenu) {
+		return
+	}
+
+	if nav.inFavoritesMenu() {
+		removed := nav.config.Favorites[nav.favoriteIndices[index]]
+		nav.config.Favorites = append(nav.config.Favorites[:nav.favoriteIndices[index]],
+			nav.config.Favorites[nav.favoriteIndices[index]+1:]...)
+		nav.saveConfig()
+		nav.rebuildRootMenu()
+		nav.notify(notifyInfo, fmt.Sprintf("Removed %s from favorites", removed.Name))
+		return
+	}
+
+	item := nav.currentMenu[index]
+	if item == nav.favoritesMenu {
+		return
+	}
+
+	path := menuItemPath(nav.baseMenu, nav.originalItem(item))
+	if path == "" {
+		nav.toggleCommandFavorite(item.Command)
+		return
+	}
+
+	for i, fav := range nav.config.Favorites {
+		if fav.Path == path {
+			nav.config.Favorites = append(nav.config.Favorites[:i], nav.config.Favorites[i+1:]...)
+			nav.saveConfig()
+			nav.rebuildRootMenu()
+			nav.notify(notifyInfo, fmt.Sprintf("Removed %s from favorites", item.Name))
+			return
+		}
+	}
+
+	nav.config.Favorites = append(nav.config.Favorites, Favorite{Name: item.Name, Path: path})
+	nav.saveConfig()
+	nav.rebuildRootMenu()
+	nav.notify(notifySuccess, fmt.Sprintf("Added %s to favorites", item.Name))
+}
+
+// toggleCommandFavorite stars or unstars a raw command such as a custom
+// command or a history entry.
+func (nav *OCNavigator) toggleCommandFavorite(command string) {
+	if command == "" {
+		return
+	}
+
+	for i, fav := range nav.config.Favorites {
+		if fav.Path == "" && fav.Command == command {
+			nav.config.Favorites = append(nav.config.Favorites[:i], nav.config.Favorites[i+1:]...)
+			nav.saveConfig()
+			nav.rebuildRootMenu()
+			nav.notify(notifyInfo, "Removed command from favorites")
+			return
+		}
+	}
+
+	nav.config.Favorites = append(nav.config.Favorites, Favorite{Name: command, Command: command})
+	nav.saveConfig()
+	nav.rebuildRootMenu()
+	nav.notify(notifySuccess, "Added command to favorites")
+}
+
+// isCommandFavorite reports whether command has been starred.
+func (nav *OCNavigator) isCommandFavorite(command string) bool {
+	for _, fav := range nav.config.Favorites {
+		if fav.Path == "" && fav.Command == command {
+			return true
+		}
+	}
+	return false
+}
+
+// moveFavorite moves the selected favorite up (delta -1) or down (delta 1)
+// among the favorites visible on this context.
+func (nav *OCNavigator) moveFavorite(delta int) {
+	index := nav.menuList.GetCurrentItem()
+	target := index + delta
+	if target < 0 || target >= len(nav.favoriteIndices) {
+		return
+	}
+
+	a, b := nav.favoriteIndices[index], nav.favoriteIndices[target]
+	nav.config.Favorites[a], nav.config.Favorites[b] = nav.config.Favorites[b], nav.config.Favorites[a]
+	nav.saveConfig()
+	nav.rebuildRootMenu()
+	nav.menuList.SetCurrentItem(target)
+}
+
+// toggleFavoriteScope limits the selected favorite to the current context,
+// or makes a context-scoped favorite global again.
+func (nav *OCNavigator) toggleFavoriteScope() {
+	index := nav.menuList.GetCurrentItem()
+	if index < 0 || index >= len(nav.favoriteIndices) {
+		return
+	}
+
+	fav := &nav.config.Favorites[nav.favoriteIndices[index]]
+	if fav.Context == "" {
+		fav.Context = nav.currentContext
+		nav.notify(notifyInfo, fmt.Sprintf("%s now only shows on %s", fav.Name, fav.Context))
+	} else {
+		fav.Context = ""
+		nav.notify(notifyInfo, fmt.Sprintf("%s now shows on every context", fav.Name))
+	}
+	nav.saveConfig()
+	nav.rebuildRootMenu()
+}
+
+// handleFavoriteAction runs a favorite action while the menu has focus.
+// Reordering and scoping only apply inside the Favorites menu. It reports
+// whether the action was handled.
+func (nav *OCNavigator) handleFavoriteAction(action keyAction) bool {
+	switch {
+	case action == actionFavorite:
+		nav.toggleFavorite()
+	case action == actionFavoriteUp && nav.inFavoritesMenu():
+		nav.moveFavorite(-1)
+	case action == actionFavoriteDown && nav.inFavoritesMenu():
+		nav.moveFavorite(1)
+	case action == actionFavoriteScope && nav.inFavoritesMenu():
+		nav.toggleFavoriteScope()
+	default:
+		return false
+	}
+	return true
+}
+
+// menuItemPath returns the slash separated names leading to target in menu,
+// or an empty string if target is not part of it.
+func menuItemPath(menu []*MenuItem, target *MenuItem) string {
+	for _, item := range menu {
+		if item == target {
+			return item.Name
+		}
+		if path := menuItemPath(item.Submenu, target); path != "" {
+			return item.Name + "/" + path
+		}
+	}
+	return ""
+}
+
+// showCommandHistory lists previously executed commands. Enter runs the
+// selected command again and '*' stars it.
+func (nav *OCNavigator) showCommandHistory() {
+	list := tview.NewList().ShowSecondaryText(false)
+
+	historyText := func(i int) string {
+		text := fmt.Sprintf("%d. %s", i+1, nav.commandHistory[i])
+		if nav.isCommandFavorite(nav.commandHistory[i]) {
+			text = "★ " + text
+		}
+		return text
+	}
+
+	for i := range nav.commandHistory {
+		list.AddItem(historyText(i), "", 0, nil)
+	}
+	if len(nav.commandHistory) == 0 {
+		list.AddItem("No commands in history", "", 0, nil)
+	}
+
+	list.SetSelectedFunc(func(index int, mainText string, secondaryText string, shortcut rune) {
+		if index < len(nav.commandHistory) {
+			command := nav.commandHistory[index]
+			nav.closeOverlay()
+			nav.executeCommand(command)
+		}
+	})
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		index := list.GetCurrentItem()
+		switch {
+		case event.Key() == tcell.KeyEscape:
+			nav.closeOverlay()
+			return nil
+		case nav.keymap.is(event, actionFavorite) && index < len(nav.commandHistory):
+			nav.toggleCommandFavorite(nav.commandHistory[index])
+			list.SetItemText(index, historyText(index), "")
+			return nil
+		}
+		return event
+	})
+
+	list.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Command History (Enter: run, %s: star, ESC: close) ", nav.keymap.label(actionFavorite))).
+		SetTitleAlign(tview.AlignLeft)
+	nav.showOverlay(list)
+}
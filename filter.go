@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// sessionFilter is a label and field selector appended to every list command
+// run from the menu until it is cleared.
+type sessionFilter struct {
+	labels string
+	fields string
+}
+
+func (f sessionFilter) empty() bool {
+	return f.labels == "" && f.fields == ""
+}
+
+// flags returns the selector options for the oc command line.
+func (f sessionFilter) flags() string {
+	var flags []string
+	if f.labels != "" {
+		flags = append(flags, "-l "+f.labels)
+	}
+	if f.fields != "" {
+		flags = append(flags, "--field-selector "+f.fields)
+	}
+	return strings.Join(flags, " ")
+}
+
+// selectorFlagPattern matches selector options already present in a command.
+var selectorFlagPattern = regexp.MustCompile(`(?:^|\s)(?:-l|--selector|--field-selector)(?:[\s=]|$)`)
+
+// valueFlags are the options of "oc get" that take their value as the next
+// argument, such as "-n demo".
+var valueFlags = map[string]bool{
+	"-n": true, "--namespace": true, "-o": true, "--output": true,
+	"-l": true, "--selector": true, "--field-selector": true,
+	"-L": true, "--label-columns": true, "--sort-by": true, "--template": true,
+	"--context": true, "--cluster": true, "--user": true, "--kubeconfig": true,
+	"-s": true, "--server": true, "--token": true, "--as": true, "--as-group": true,
+	"--chunk-size": true, "--request-timeout": true,
+}
+
+// positionalArgs returns the arguments of a command line that are neither
+// options nor the values of options.
+func positionalArgs(args []string) []string {
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		switch {
+		case valueFlags[args[i]]:
+			i++
+		case !strings.HasPrefix(args[i], "-"):
+			positional = append(positional, args[i])
+		}
+	}
+	return positional
+}
+
+// filtered appends the session filter to command when it lists resources:
+// an "oc get" of a kind without a name and without selectors of its own.
+// Options may come before the kind, as in "oc get -A pods".
+func (nav *OCNavigator) filtered(command string) string {
+	if nav.filter.empty() || !isListCommand(command) || selectorFlagPattern.MatchString(command) {
+		return command
+	}
+	args := positionalArgs(strings.Fields(command)[2:])
+	if len(args) != 1 || strings.Contains(args[0], "/") {
+		return command
+	}
+	return command + " " + nav.filter.flags()
+}
+
+// normalizeSelector drops the spaces around the commas of a selector, since
+// commands are split on whitespace.
+func normalizeSelector(selector string) (string, error) {
+	parts := strings.Split(strings.TrimSpace(selector), ",")
+	for i, part := range parts {
+		parts[i] = strings.TrimSpace(part)
+	}
+	selector = strings.Join(parts, ",")
+	if strings.ContainsAny(selector, " \t") {
+		return "", fmt.Errorf("selector %q cannot contain spaces", selector)
+	}
+	return selector, nil
+}
+
+// showFilterPrompt asks for the label selector and then the field selector
+// of the session filter. Esc at either prompt leaves the filter unchanged.
+func (nav *OCNavigator) showFilterPrompt() {
+	nav.prompt("Label selector (e.g. app=frontend): ", nav.filter.labels, nil, func(labels string, accepted bool) {
+		if !accepted {
+			return
+		}
+		nav.prompt("Field selector (e.g. status.phase!=Running): ", nav.filter.fields, nil, func(fields string, accepted bool) {
+			if accepted {
+				nav.setFilter(labels, fields)
+			}
+		})
+	})
+}
+
+// setFilter validates and applies a new session filter.
+func (nav *OCNavigator) setFilter(labels, fields string) {
+	labels, err := normalizeSelector(labels)
+	if err == nil {
+		fields, err = normalizeSelector(fields)
+	}
+	if err != nil {
+		nav.notify(notifyError, err.Error())
+		return
+	}
+
+	nav.filter = sessionFilter{labels: labels, fields: fields}
+	nav.updateStatusBar()
+	if nav.filter.empty() {
+		nav.notify(notifyInfo, "Filter cleared")
+		return
+	}
+	nav.notify(notifySuccess, fmt.Sprintf("List commands from the menu now run with %s", nav.filter.flags()))
+}
+
+// clearFilter removes the session filter.
+func (nav *OCNavigator) clearFilter() {
+	if nav.filter.empty() {
+		nav.notify(notifyInfo, "No filter is set")
+		return
+	}
+	nav.setFilter("", "")
+}
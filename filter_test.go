@@ -0,0 +1,96 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rivo/tview"
+)
+
+func TestSessionFilterFiltered(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  sessionFilter
+		command string
+		want    string
+	}{
+		{"no filter", sessionFilter{}, "oc get pods", "oc get pods"},
+		{"labels", sessionFilter{labels: "app=web"}, "oc get pods", "oc get pods -l app=web"},
+		{"fields", sessionFilter{fields: "status.phase!=Running"}, "oc get pods -o wide",
+			"oc get pods -o wide --field-selector status.phase!=Running"},
+		{"both", sessionFilter{labels: "app=web,tier=front", fields: "spec.nodeName=worker-1"}, "oc get pods",
+			"oc get pods -l app=web,tier=front --field-selector spec.nodeName=worker-1"},
+		{"named resource", sessionFilter{labels: "app=web"}, "oc get pod web-1", "oc get pod web-1"},
+		{"slash name", sessionFilter{labels: "app=web"}, "oc get pod/web-1", "oc get pod/web-1"},
+		{"own labels", sessionFilter{labels: "app=web"}, "oc get pods -l app=db", "oc get pods -l app=db"},
+		{"own selector", sessionFilter{labels: "app=web"}, "oc get pods --selector=app=db", "oc get pods --selector=app=db"},
+		{"own field selector", sessionFilter{labels: "app=web"}, "oc get pods --field-selector x=y", "oc get pods --field-selector x=y"},
+		{"flag before kind", sessionFilter{labels: "app=web"}, "oc get -A pods", "oc get -A pods -l app=web"},
+		{"flag value before kind", sessionFilter{labels: "app=web"}, "oc get -n demo pods", "oc get -n demo pods -l app=web"},
+		{"flag value before name", sessionFilter{labels: "app=web"}, "oc get -n demo pod web-1", "oc get -n demo pod web-1"},
+		{"flag with equals", sessionFilter{labels: "app=web"}, "oc get --namespace=demo pods -o wide",
+			"oc get --namespace=demo pods -o wide -l app=web"},
+		{"no kind", sessionFilter{labels: "app=web"}, "oc get", "oc get"},
+		{"not a list", sessionFilter{labels: "app=web"}, "oc describe pods", "oc describe pods"},
+		{"label value with l", sessionFilter{labels: "app=web"}, "oc get pods -n label-test", "oc get pods -n label-test -l app=web"},
+	}
+	for _, tt := range tests {
+		nav := &OCNavigator{filter: tt.filter}
+		if got := nav.filtered(tt.command); got != tt.want {
+			t.Errorf("%s: filtered(%q) = %q, want %q", tt.name, tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeSelector(t *testing.T) {
+	tests := []struct {
+		selector string
+		want     string
+		err      bool
+	}{
+		{"app=web", "app=web", false},
+		{" app=web , tier!=front ", "app=web,tier!=front", false},
+		{"", "", false},
+		{"env in (prod, staging)", "", true},
+		{"app = web", "", true},
+	}
+	for _, tt := range tests {
+		got, err := normalizeSelector(tt.selector)
+		if (err != nil) != tt.err || got != tt.want {
+			t.Errorf("normalizeSelector(%q) = %q, %v, want %q, error %v", tt.selector, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestStatusBarKeepsFilterDuringNotifications(t *testing.T) {
+	keymap, _ := buildKeymap(nil, false)
+	notifications, _ := newNotifier(nil)
+	nav := &OCNavigator{
+		statusBar:      tview.NewTextView().SetDynamicColors(true),
+		keymap:         keymap,
+		notifications:  notifications,
+		theme:          darkTheme,
+		currentContext: "dev",
+		currentProject: "demo",
+		filter:         sessionFilter{labels: "app=web"},
+	}
+	nav.updateStatusBar()
+	status := nav.statusBar.GetText(true)
+	for _, want := range []string{"Filter: -l app=web", "Project: demo", "Help"} {
+		if !strings.Contains(status, want) {
+			t.Errorf("status bar %q does not contain %q", status, want)
+		}
+	}
+
+	nav.notifications.push(notifyInfo, "Executing: oc get pods")
+	nav.updateStatusBar()
+	status = nav.statusBar.GetText(true)
+	for _, want := range []string{"Filter: -l app=web", "Executing: oc get pods", "Context: dev", "Project: demo"} {
+		if !strings.Contains(status, want) {
+			t.Errorf("status bar with a notification %q does not contain %q", status, want)
+		}
+	}
+	if strings.Index(status, "Filter:") > strings.Index(status, "Executing:") {
+		t.Errorf("status bar %q shows the notification ahead of the filter", status)
+	}
+}
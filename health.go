@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+// healthCheckInterval is how often the cluster identity and connectivity are
+// refreshed in the background.
+const healthCheckInterval = 30 * time.Second
+
+// healthCheckTimeout bounds every oc call made by a single check.
+const healthCheckTimeout = 10 * time.Second
+
+type connState int
+
+const (
+	connUnknown connState = iota
+	connOK
+	connUnauthorized
+	connUnreachable
+	connError
+)
+
+// clusterHealth is the result of one background check.
+type clusterHealth struct {
+	state   connState
+	user    string
+	server  string
+	version string
+	err     string
+}
+
+// healthChecker refreshes clusterHealth on an interval, or immediately when
+// poked, without ever running oc on the event loop.
+type healthChecker struct {
+	mu     sync.Mutex
+	health clusterHealth
+	poke   chan struct{}
+	cancel context.CancelFunc
+}
+
+// startHealthCheck launches the background checker.
+func (nav *OCNavigator) startHealthCheck() {
+	ctx, cancel := context.WithCancel(context.Background())
+	hc := &healthChecker{poke: make(chan struct{}, 1), cancel: cancel}
+	nav.health = hc
+
+	go func() {
+		ticker := time.NewTicker(healthCheckInterval)
+		defer ticker.Stop()
+		for {
+			health := checkClusterHealth(ctx, nav.cli)
+			if ctx.Err() != nil {
+				return
+			}
+			hc.mu.Lock()
+			previous := hc.health.state
+			hc.health = health
+			hc.mu.Unlock()
+
+			if health.state != previous {
+				switch health.state {
+				case connUnauthorized:
+					nav.notify(notifyError, "Token expired or invalid: log in again")
+				case connUnreachable:
+					nav.notify(notifyError, "Cannot reach the API server: "+health.err)
+				case connOK:
+					if previous != connUnknown {
+						nav.notify(notifySuccess, "Connection to the cluster restored")
+					}
+				}
+			}
+			nav.app.QueueUpdateDraw(nav.updateStatusBar)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-hc.poke:
+			}
+		}
+	}()
+}
+
+// checkHealthNow asks the checker to run again without waiting for the interval.
+func (nav *OCNavigator) checkHealthNow() {
+	if nav.health == nil {
+		return
+	}
+	select {
+	case nav.health.poke <- struct{}{}:
+	default:
+	}
+}
+
+// stopHealthCheck stops the background checker.
+func (nav *OCNavigator) stopHealthCheck() {
+	if nav.health != nil {
+		nav.health.cancel()
+	}
+}
+
+// current returns the latest check result.
+func (hc *healthChecker) current() clusterHealth {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	return hc.health
+}
+
+// checkClusterHealth asks oc who we are, where the API server is and which
+// version it runs. "oc whoami" is the call that needs a valid token, so its
+// error decides the connection state.
+func checkClusterHealth(ctx context.Context, tool cliTool) clusterHealth {
+	var health clusterHealth
+
+	if server, err := ocOutput(ctx, tool, "whoami", "--show-server"); err == nil {
+		health.server = server
+	}
+
+	user, err := ocOutput(ctx, tool, "whoami")
+	if err != nil {
+		health.state, health.err = connStateOf(err)
+		return health
+	}
+	health.user = user
+	health.state = connOK
+
+	if out, err := ocOutput(ctx, tool, "version", "-o", "json"); err == nil {
+		health.version = serverVersion(out)
+	}
+	return health
+}
+
+// ocOutput runs oc through tool with a timeout and returns its trimmed stdout. On failure
+// the error is classified from stderr like the errors of runCommand.
+func ocOutput(ctx context.Context, tool cliTool, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd, err := tool.command(ctx, args...)
+	if err != nil {
+		return "", err
+	}
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("timed out after %s: %w", healthCheckTimeout, context.DeadlineExceeded)
+	}
+	if err != nil {
+		return "", classifyError(err, stderr.String())
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// connStateOf tells an expired token apart from a server that cannot be
+// reached, from the category classifyError gave the failure. The message is
+// what oc said, which is more precise than the summary.
+func connStateOf(err error) (connState, string) {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return connUnreachable, err.Error()
+	}
+	var e *commandError
+	if !errors.As(err, &e) {
+		return connError, err.Error()
+	}
+	msg := firstLine(e.stderr, e)
+	switch e.category {
+	case errUnauthorized:
+		return connUnauthorized, msg
+	case errConnection:
+		return connUnreachable, msg
+	}
+	return connError, msg
+}
+
+// serverVersion picks the most useful version from "oc version -o json":
+// the OpenShift version when there is one, otherwise the Kubernetes one.
+func serverVersion(out string) string {
+	var version struct {
+		OpenShiftVersion string `json:"openshiftVersion"`
+		ServerVersion    struct {
+			GitVersion string `json:"gitVersion"`
+		} `json:"serverVersion"`
+	}
+	if err := json.Unmarshal([]byte(out), &version); err != nil {
+		return ""
+	}
+	if version.OpenShiftVersion != "" {
+		return "OCP " + version.OpenShiftVersion
+	}
+	return version.ServerVersion.GitVersion
+}
+
+// serverHost shortens an API server URL to its host and port.
+func serverHost(server string) string {
+	if u, err := url.Parse(server); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return server
+}
+
+// healthStatus renders the connection indicator and cluster identity for the
+// status bar.
+func (nav *OCNavigator) healthStatus() string {
+	if nav.health == nil {
+		return ""
+	}
+	health := nav.health.current()
+
+	var indicator string
+	switch health.state {
+	case connUnknown:
+		return nav.theme.paint(roleMuted, " ○ checking…") + " |"
+	case connOK:
+		indicator = nav.theme.paint(roleSuccess, "●")
+	case connUnauthorized:
+		indicator = nav.theme.paint(roleError, "✗ TOKEN EXPIRED")
+	case connUnreachable:
+		indicator = nav.theme.paint(roleError, "✗ UNREACHABLE")
+	default:
+		indicator = nav.theme.paint(roleWarning, "✗ ERROR")
+	}
+
+	parts := []string{indicator}
+	if health.user != "" {
+		parts = append(parts, nav.theme.paint(roleContext, tview.Escape(health.user)))
+	}
+	if health.server != "" {
+		parts = append(parts, "@ "+tview.Escape(serverHost(health.server)))
+	}
+	if health.version != "" {
+		parts = append(parts, nav.theme.paint(roleMuted, "("+tview.Escape(health.version)+")"))
+	}
+	return " " + strings.Join(parts, " ") + " |"
+}
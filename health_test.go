@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCheckClusterHealth(t *testing.T) {
+	tests := []struct {
+		name   string
+		stderr string
+		state  connState
+		err    string
+	}{
+		{
+			name:   "refused",
+			stderr: "The connection to the server api.example.com:6443 was refused - did you specify the right host or port?",
+			state:  connUnreachable,
+			err:    "The connection to the server api.example.com:6443 was refused - did you specify the right host or port?",
+		},
+		{
+			name:   "timeout",
+			stderr: "Unable to connect to the server: dial tcp 10.0.0.1:6443: i/o timeout",
+			state:  connUnreachable,
+			err:    "Unable to connect to the server: dial tcp 10.0.0.1:6443: i/o timeout",
+		},
+		{
+			name:   "unauthorized",
+			stderr: "error: You must be logged in to the server (Unauthorized)",
+			state:  connUnauthorized,
+			err:    "error: You must be logged in to the server (Unauthorized)",
+		},
+		{
+			name:   "other",
+			stderr: "error: unexpected response",
+			state:  connError,
+			err:    "error: unexpected response",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oc := fakeOC(t, "echo '"+tt.stderr+"' >&2\nexit 1\n")
+			health := checkClusterHealth(context.Background(), cliTool{path: oc})
+			if health.state != tt.state {
+				t.Errorf("state = %v, want %v", health.state, tt.state)
+			}
+			if health.err != tt.err {
+				t.Errorf("err = %q, want %q", health.err, tt.err)
+			}
+		})
+	}
+}
+
+func TestCheckClusterHealthConnected(t *testing.T) {
+	oc := fakeOC(t, `case "$*" in
+  "whoami --show-server") echo https://api.example.com:6443;;
+  "whoami") echo developer;;
+  "version -o json") echo '{"openshiftVersion": "4.14.8", "serverVersion": {"gitVersion": "v1.27.8"}}';;
+esac
+`)
+	want := clusterHealth{state: connOK, user: "developer", server: "https://api.example.com:6443", version: "OCP 4.14.8"}
+	if got := checkClusterHealth(context.Background(), cliTool{path: oc}); got != want {
+		t.Errorf("checkClusterHealth() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// keyHelp describes what a key does in a view.
+type keyHelp struct {
+	keys        string
+	description string
+}
+
+// currentView names the part of the UI that currently receives keys.
+func (nav *OCNavigator) currentView() string {
+	switch {
+	case nav.overlay != nil:
+		if _, ok := nav.overlay.(*tview.Form); ok {
+			return "form"
+		}
+		return "dialog"
+	case nav.app.GetFocus() == nav.resourceTable:
+		return "table"
+	case nav.app.GetFocus() == nav.documentTree:
+		return "document"
+	case nav.logStream != nil:
+		return "log stream"
+	default:
+		return "menu"
+	}
+}
+
+// viewKeys lists the keys that apply in view.
+func (nav *OCNavigator) viewKeys(view string) []keyHelp {
+	keys := nav.keymap
+	switch view {
+	case "form":
+		return []keyHelp{
+			{"Tab/Backtab", "Move between fields and buttons"},
+			{"Enter", "Press the focused button"},
+			{"Esc", "Cancel"},
+		}
+	case "dialog":
+		dialog := []keyHelp{
+			{"Up/Down", "Move the selection"},
+			{"Enter", "Choose"},
+			{"Esc", "Close"},
+		}
+		if nav.config.VimKeys {
+			dialog = append(dialog, keyHelp{"q", "Close"})
+		}
+		return dialog
+	case "table":
+		return append([]keyHelp{
+			{"Up/Down/PgUp/PgDn", "Select a row"},
+			{"Enter/right click", "Actions for the selected row"},
+			{"c", "Switch to the next column set"},
+			{keys.label(actionBack), "Stop the live watch and close the table"},
+		}, nav.vimHelp(view)...)
+	case "document":
+		return []keyHelp{
+			{"Up/Down/PgUp/PgDn", "Select a field"},
+			{"Enter", "Fold or unfold the selected mapping or list"},
+			{"m", "Hide or show managedFields and status"},
+			{"y", "Copy the JSONPath of the selected field"},
+			{keys.label(actionExplain), "Explain the selected field"},
+			{keys.label(actionBack), "Close the document and show plain output"},
+		}
+	case "log stream":
+		return append([]keyHelp{
+			{"Up/Down/PgUp/PgDn", "Scroll"},
+			{"Home/End", "Jump to the beginning or end and keep following"},
+			{keys.label(actionBack), "Stop following logs"},
+		}, nav.vimHelp(view)...)
+	}
+
+	menu := []keyHelp{
+		{"Up/Down", "Move the selection"},
+		{"Enter/double click", "Open submenu or run command"},
+		{"1-9", "Pick item by its shortcut"},
+		{keys.label(actionBack), "Back to the previous menu, quit from the main menu"},
+		{"Alt+0..9", "Jump to a breadcrumb level, Alt+0 goes home"},
+		{keys.label(actionPalette), "Command palette, type a number to pick that item"},
+		{keys.label(actionFavorite), "Star or unstar the selected item"},
+	}
+	if nav.inFavoritesMenu() {
+		menu = append(menu,
+			keyHelp{keys.label(actionFavoriteUp) + "/" + keys.label(actionFavoriteDown), "Move the favorite up or down"},
+			keyHelp{keys.label(actionFavoriteScope), "Show the favorite only on the current context"})
+	}
+	return append(menu, nav.vimHelp(view)...)
+}
+
+// vimHelp lists the vi-style keys of view when they are enabled.
+func (nav *OCNavigator) vimHelp(view string) []keyHelp {
+	if !nav.config.VimKeys {
+		return nil
+	}
+
+	keys := []keyHelp{
+		{"j/k", "Down/up"},
+		{"g/G", "First/last"},
+		{"Ctrl+D/Ctrl+U", "Half a page down/up"},
+		{"/", "Search the output pane"},
+		{"n/N", "Next/previous match"},
+	}
+	if view == "menu" {
+		keys = append(keys,
+			keyHelp{"h", "Back to the previous menu"},
+			keyHelp{"l", "Open submenu or run command"})
+	}
+	return keys
+}
+
+// globalKeys lists the keys available in every view of the main layout.
+func (nav *OCNavigator) globalKeys() []keyHelp {
+	keys := nav.keymap
+	return []keyHelp{
+		{keys.label(actionHelp), "This help"},
+		{keys.label(actionFocusNext) + "/" + keys.label(actionFocusPrev), "Move focus between panes, or click a pane"},
+		{keys.label(actionPaneGrow) + "/" + keys.label(actionPaneShrink), "Grow or shrink the focused pane"},
+		{keys.label(actionZoom), "Zoom the focused pane, again to restore"},
+		{keys.label(actionLayout), "Switch between auto, stacked and side-by-side layout"},
+		{keys.label(actionHistory), "Command history"},
+		{keys.label(actionNotifications), "Recent notifications"},
+		{keys.label(actionLogin), "Log in to a cluster (log out from the Session menu)"},
+		{keys.label(actionCustomCommand), "Run a custom command"},
+		{keys.label(actionWatch), "Re-run the last list command on an interval"},
+		{keys.label(actionLiveWatch), "Live table for the last list command"},
+		{keys.label(actionExplain), "Browse the schema of the listed kind"},
+		{keys.label(actionQuery), "Query the last JSON output with JSONPath or jq syntax"},
+		{keys.label(actionFilter), "Set a label and field selector for list commands from the menu"},
+		{keys.label(actionClearFilter), "Clear the selector filter"},
+		{keys.label(actionRefresh), "Refresh context and project"},
+		{keys.label(actionQuit), "Quit"},
+	}
+}
+
+// showHelp opens an overlay with the keys of the current view, the selected
+// item's command and, for resource lists, a summary from "oc explain".
+func (nav *OCNavigator) showHelp() {
+	view := nav.currentView()
+	previousOverlay, previousPage := nav.overlay, nav.overlayPage
+	previousFocus := nav.app.GetFocus()
+
+	var item *MenuItem
+	if index := nav.menuList.GetCurrentItem(); index >= 0 && index < len(nav.currentMenu) {
+		item = nav.currentMenu[index]
+	}
+	kind := ""
+	if item != nil {
+		kind = resourceKind(item.Command)
+	}
+
+	helpView := tview.NewTextView().SetDynamicColors(true).SetScrollable(true).SetWrap(true)
+	nav.helpView = helpView
+	helpView.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Help: %s (Esc/q to close) ", view)).
+		SetTitleAlign(tview.AlignLeft)
+
+	render := func(explain string) {
+		var text strings.Builder
+		writeKeys := func(title string, keys []keyHelp) {
+			fmt.Fprintf(&text, "%s\n", nav.theme.paint(roleTitle, title))
+			for _, k := range keys {
+				fmt.Fprintf(&text, "  %s %s\n", nav.theme.paint(roleKey, fmt.Sprintf("%-20s", tview.Escape(k.keys))), k.description)
+			}
+			text.WriteString("\n")
+		}
+
+		writeKeys(strings.ToUpper(view[:1])+view[1:]+" keys", nav.viewKeys(view))
+		if previousOverlay == nil {
+			writeKeys("Global keys", nav.globalKeys())
+		}
+
+		if item != nil {
+			fmt.Fprintf(&text, "%s\n  %s\n", nav.theme.paint(roleTitle, "Selected item"), tview.Escape(item.Name))
+			if item.Command != "" {
+				fmt.Fprintf(&text, "  %s %s\n", nav.theme.paint(roleKey, "Command:"), tview.Escape(item.Command))
+			}
+			text.WriteString("\n")
+		}
+		if kind != "" {
+			fmt.Fprintf(&text, "%s\n  %s\n", nav.theme.paint(roleTitle, "oc explain "+tview.Escape(kind)), tview.Escape(explain))
+		}
+		helpView.SetText(text.String())
+	}
+
+	closeHelp := func() {
+		if previousOverlay != nil {
+			nav.showOverlayPage(previousOverlay, previousPage)
+		} else {
+			nav.closeOverlay()
+		}
+		nav.app.SetFocus(previousFocus)
+	}
+	helpView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape || (event.Key() == tcell.KeyRune && event.Rune() == 'q') ||
+			nav.keymap.is(event, actionHelp) {
+			closeHelp()
+			return nil
+		}
+		return event
+	})
+
+	render("loading...")
+	nav.showOverlay(helpView)
+
+	if kind != "" {
+		nav.explainSummary(kind, func(summary string) {
+			if nav.overlay == helpView {
+				render(summary)
+			}
+		})
+	}
+}
+
+// helpShown reports whether the help overlay is open.
+func (nav *OCNavigator) helpShown() bool {
+	return nav.helpView != nil && nav.overlay == nav.helpView
+}
+
+// resourceKind returns the resource kind listed by an "oc get <kind>"
+// command, or an empty string for any other command.
+func resourceKind(command string) string {
+	fields := strings.Fields(command)
+	if len(fields) < 3 || fields[0] != "oc" || fields[1] != "get" || strings.HasPrefix(fields[2], "-") {
+		return ""
+	}
+	return fields[2]
+}
+
+// explainSummary calls done with a short description of kind from
+// "oc explain". Results are cached; the lookup runs off the event loop.
+func (nav *OCNavigator) explainSummary(kind string, done func(summary string)) {
+	if summary, ok := nav.explainCache[kind]; ok {
+		done(summary)
+		return
+	}
+
+	go func() {
+		output, _, err := runCommand(nav.cli, "oc explain "+kind)
+		summary := explainDescription(output)
+		if err != nil {
+			summary = fmt.Sprintf("not available (%v)", err)
+		}
+
+		nav.app.QueueUpdateDraw(func() {
+			if err == nil {
+				nav.explainCache[kind] = summary
+			}
+			done(summary)
+		})
+	}()
+}
+
+// explainDescription extracts the DESCRIPTION section of "oc explain"
+// output as a single paragraph, shortened to a few sentences.
+func explainDescription(output string) string {
+	summary := explainText(output)
+	if summary == "" {
+		return "no description"
+	}
+	const maxSummary = 400
+	if runes := []rune(summary); len(runes) > maxSummary {
+		summary = string(runes[:maxSummary]) + "..."
+	}
+	return summary
+}
+
+// explainText returns the whole DESCRIPTION section of "oc explain" output
+// as a single paragraph.
+func explainText(output string) string {
+	var description []string
+	inDescription := false
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "DESCRIPTION:"):
+			inDescription = true
+			if rest := strings.TrimSpace(strings.TrimPrefix(line, "DESCRIPTION:")); rest != "" {
+				description = append(description, rest)
+			}
+		case inDescription && trimmed != "" && line[0] != ' ' && line[0] != '\t':
+			inDescription = false
+		case inDescription && trimmed != "":
+			description = append(description, trimmed)
+		}
+	}
+
+	return strings.Join(description, " ")
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+func TestExplainDescription(t *testing.T) {
+	long := strings.Repeat("é", 399) + "ü and more"
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{
+			name:   "description",
+			output: "KIND:     Pod\nVERSION:  v1\n\nDESCRIPTION:\n     Pod is a collection of containers\n     that can run on a host.\n\nFIELDS:\n   apiVersion\t<string>\n",
+			want:   "Pod is a collection of containers that can run on a host.",
+		},
+		{name: "no description", output: "KIND:     Pod\n", want: "no description"},
+		{name: "cut on runes", output: "DESCRIPTION:\n  " + long + "\n", want: strings.Repeat("é", 399) + "ü..."},
+	}
+	for _, tt := range tests {
+		got := explainDescription(tt.output)
+		if got != tt.want {
+			t.Errorf("%s: explainDescription() = %q, want %q", tt.name, got, tt.want)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("%s: explainDescription() is not valid UTF-8", tt.name)
+		}
+	}
+}
+
+func TestCloseHelpKeepsOverlayCentered(t *testing.T) {
+	nav := newTestNavigator(t, "echo test\n")
+	form := tview.NewForm()
+	nav.showCenteredOverlay(form, 70, 15)
+	_, page := nav.pages.GetFrontPage()
+
+	nav.showHelp()
+	pressKeys(nav, tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+
+	if nav.overlay != form {
+		t.Fatalf("overlay = %T, want the form opened before the help", nav.overlay)
+	}
+	if _, got := nav.pages.GetFrontPage(); got != page {
+		t.Errorf("front page = %T, want the form centered as before", got)
+	}
+}
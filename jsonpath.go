@@ -0,0 +1,405 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// evalQuery evaluates a query against a document. Queries are JSONPath as
+// understood by "oc get -o jsonpath", such as {.items[*].metadata.name} or
+// {.items[?(@.status.phase=="Running")].metadata.name}, or a jq-like subset:
+// paths with .[] and stages joined by | that may also be select(cond),
+// length or keys.
+func evalQuery(root *docNode, query string) ([]*docNode, error) {
+	query = strings.TrimSpace(query)
+	if strings.HasPrefix(query, "{") && strings.HasSuffix(query, "}") {
+		query = strings.TrimSpace(query[1 : len(query)-1])
+	}
+	if query == "" {
+		return []*docNode{root}, nil
+	}
+
+	nodes := []*docNode{root}
+	for _, stage := range splitTopLevel(query, '|') {
+		stage = strings.TrimSpace(stage)
+		var err error
+		switch {
+		case stage == "length":
+			nodes = mapNodes(nodes, func(n *docNode) *docNode {
+				count := len(n.children)
+				if s, ok := n.value.(string); ok && !n.container() {
+					count = len([]rune(s))
+				}
+				return &docNode{index: -1, value: json.Number(strconv.Itoa(count))}
+			})
+		case stage == "keys":
+			nodes = mapNodes(nodes, keysNode)
+		case strings.HasPrefix(stage, "select(") && strings.HasSuffix(stage, ")"):
+			var kept []*docNode
+			for _, n := range nodes {
+				ok, condErr := evalCondition(n, stage[len("select("):len(stage)-1])
+				if condErr != nil {
+					return nil, condErr
+				}
+				if ok {
+					kept = append(kept, n)
+				}
+			}
+			nodes = kept
+		default:
+			nodes, err = evalPath(nodes, stage)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
+func mapNodes(nodes []*docNode, f func(*docNode) *docNode) []*docNode {
+	mapped := make([]*docNode, len(nodes))
+	for i, n := range nodes {
+		mapped[i] = f(n)
+	}
+	return mapped
+}
+
+// keysNode returns the sorted member names of an object, or the indices of
+// an array, as jq does.
+func keysNode(n *docNode) *docNode {
+	keys := &docNode{index: -1, array: true}
+	if n.array {
+		for i := range n.children {
+			keys.children = append(keys.children, &docNode{index: i, value: json.Number(strconv.Itoa(i)), parent: keys})
+		}
+		return keys
+	}
+	var names []string
+	for _, c := range n.children {
+		if n.object {
+			names = append(names, c.key)
+		}
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		keys.children = append(keys.children, &docNode{index: i, value: name, parent: keys})
+	}
+	return keys
+}
+
+// scanTopLevel calls visit with the offset of every character of s outside of
+// quotes, brackets and parentheses, until visit returns false.
+func scanTopLevel(s string, visit func(i int) bool) {
+	depth := 0
+	var quote rune
+	for i, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '[' || r == '(':
+			depth++
+		case r == ']' || r == ')':
+			depth--
+		case depth == 0:
+			if !visit(i) {
+				return
+			}
+		}
+	}
+}
+
+// splitTopLevel splits s on sep outside of quotes, brackets and parentheses.
+func splitTopLevel(s string, sep rune) []string {
+	var parts []string
+	start := 0
+	scanTopLevel(s, func(i int) bool {
+		if strings.HasPrefix(s[i:], string(sep)) {
+			parts = append(parts, s[start:i])
+			start = i + len(string(sep))
+		}
+		return true
+	})
+	return append(parts, s[start:])
+}
+
+// evalPath applies a path such as .spec.containers[0].image, ..name or
+// .items[?(@.kind=="Pod")] to every node.
+func evalPath(nodes []*docNode, path string) ([]*docNode, error) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), "@")
+	for i := 0; i < len(path); {
+		switch {
+		case strings.HasPrefix(path[i:], ".."):
+			i += 2
+			name, next := readName(path, i)
+			if name == "" {
+				return nil, fmt.Errorf("expected a field name after .. at %q", path[i:])
+			}
+			var found []*docNode
+			for _, n := range nodes {
+				found = append(found, descendants(n, name)...)
+			}
+			nodes, i = found, next
+		case path[i] == '.':
+			i++
+			if i == len(path) || path[i] == '[' {
+				continue
+			}
+			name, next := readName(path, i)
+			if name == "" {
+				return nil, fmt.Errorf("expected a field name at %q", path[i:])
+			}
+			nodes, i = members(nodes, name), next
+		case path[i] == '[':
+			end := matchingBracket(path, i)
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed [ in %q", path[i:])
+			}
+			var err error
+			if nodes, err = subscript(nodes, strings.TrimSpace(path[i+1:end])); err != nil {
+				return nil, err
+			}
+			i = end + 1
+		default:
+			return nil, fmt.Errorf("unexpected %q in %q", path[i:], path)
+		}
+	}
+	return nodes, nil
+}
+
+// readName reads a field name starting at i and returns it with the index
+// after it.
+func readName(path string, i int) (string, int) {
+	start := i
+	for i < len(path) && path[i] != '.' && path[i] != '[' && path[i] != ' ' {
+		if path[i] == '\\' && i+1 < len(path) {
+			i++
+		}
+		i++
+	}
+	return strings.ReplaceAll(path[start:i], `\.`, "."), i
+}
+
+func matchingBracket(path string, open int) int {
+	depth := 0
+	var quote byte
+	for i := open; i < len(path); i++ {
+		switch c := path[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func members(nodes []*docNode, name string) []*docNode {
+	var found []*docNode
+	for _, n := range nodes {
+		if c := n.child(name); c != nil {
+			found = append(found, c)
+		}
+	}
+	return found
+}
+
+func descendants(n *docNode, name string) []*docNode {
+	var found []*docNode
+	for _, c := range n.children {
+		if n.object && c.key == name {
+			found = append(found, c)
+		}
+		found = append(found, descendants(c, name)...)
+	}
+	return found
+}
+
+// subscript applies the inside of brackets: *, an index, a quoted member
+// name or a ?(filter).
+func subscript(nodes []*docNode, inner string) ([]*docNode, error) {
+	var found []*docNode
+	switch {
+	case inner == "" || inner == "*":
+		for _, n := range nodes {
+			found = append(found, n.children...)
+		}
+	case strings.HasPrefix(inner, "?(") && strings.HasSuffix(inner, ")"):
+		for _, n := range nodes {
+			for _, c := range n.children {
+				ok, err := evalCondition(c, inner[2:len(inner)-1])
+				if err != nil {
+					return nil, err
+				}
+				if ok {
+					found = append(found, c)
+				}
+			}
+		}
+	case inner[0] == '\'' || inner[0] == '"':
+		name, err := unquote(inner)
+		if err != nil {
+			return nil, err
+		}
+		found = members(nodes, name)
+	default:
+		index, err := strconv.Atoi(inner)
+		if err != nil {
+			return nil, fmt.Errorf("unsupported subscript [%s]", inner)
+		}
+		for _, n := range nodes {
+			i := index
+			if i < 0 {
+				i += len(n.children)
+			}
+			if n.array && i >= 0 && i < len(n.children) {
+				found = append(found, n.children[i])
+			}
+		}
+	}
+	return found, nil
+}
+
+func unquote(s string) (string, error) {
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return strings.ReplaceAll(s[1:len(s)-1], `\'`, "'"), nil
+	}
+	return strconv.Unquote(s)
+}
+
+// conditionOperators are checked longest first so that <= is not read as <.
+var conditionOperators = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+// findOperator returns the offset and the first comparison operator of
+// condition outside of quotes and brackets, or -1 when there is none.
+func findOperator(condition string) (int, string) {
+	at, found := -1, ""
+	scanTopLevel(condition, func(i int) bool {
+		for _, op := range conditionOperators {
+			if strings.HasPrefix(condition[i:], op) {
+				at, found = i, op
+				return false
+			}
+		}
+		return true
+	})
+	return at, found
+}
+
+// evalCondition evaluates a filter such as @.status.phase=="Running" or, in
+// jq style, .spec.replicas > 1 against n. A bare path tests that the field
+// exists and is neither false nor null.
+func evalCondition(n *docNode, condition string) (bool, error) {
+	condition = strings.TrimSpace(condition)
+	if i, op := findOperator(condition); i >= 0 {
+		left, err := evalPath([]*docNode{n}, strings.TrimSpace(condition[:i]))
+		if err != nil {
+			return false, err
+		}
+		right, err := parseLiteral(strings.TrimSpace(condition[i+len(op):]))
+		if err != nil {
+			return false, err
+		}
+		for _, l := range left {
+			// Objects and lists are not null, nor equal to any literal
+			if l.container() && op == "!=" || !l.container() && compareValues(l.value, right, op) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	found, err := evalPath([]*docNode{n}, condition)
+	if err != nil {
+		return false, err
+	}
+	for _, f := range found {
+		if f.container() || (f.value != nil && f.value != false) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// parseLiteral reads the right-hand side of a comparison.
+func parseLiteral(s string) (interface{}, error) {
+	switch s {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	if s != "" && (s[0] == '"' || s[0] == '\'') {
+		return unquote(s)
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return json.Number(s), nil
+	}
+	return nil, fmt.Errorf("cannot compare with %q: quote strings", s)
+}
+
+func compareValues(left, right interface{}, op string) bool {
+	if l, ok := left.(json.Number); ok {
+		if r, ok := right.(json.Number); ok {
+			lf, _ := l.Float64()
+			rf, _ := r.Float64()
+			return compareOrdered(lf, rf, op)
+		}
+	}
+	if l, ok := left.(string); ok {
+		if r, ok := right.(string); ok {
+			return compareOrdered(l, r, op)
+		}
+	}
+	switch op {
+	case "==":
+		return left == right
+	case "!=":
+		return left != right
+	}
+	return false
+}
+
+func compareOrdered[T float64 | string](l, r T, op string) bool {
+	switch op {
+	case "==":
+		return l == r
+	case "!=":
+		return l != r
+	case "<":
+		return l < r
+	case "<=":
+		return l <= r
+	case ">":
+		return l > r
+	case ">=":
+		return l >= r
+	}
+	return false
+}
+
+// queryResultText formats a result: strings bare as jsonpath prints them,
+// other scalars as JSON and objects or lists as indented JSON.
+func queryResultText(n *docNode) string {
+	if s, ok := n.value.(string); ok && !n.container() {
+		return s
+	}
+	return strings.TrimSuffix(n.text(formatJSON, false), "\n")
+}
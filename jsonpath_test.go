@@ -0,0 +1,119 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const queryDocument = `{
+  "kind": "List",
+  "items": [
+    {"metadata": {"name": "web-1", "labels": {"app": "web", "tier": "front"}},
+     "spec": {"replicas": 3, "containers": [{"name": "nginx"}, {"name": "envoy"}]},
+     "status": {"phase": "Running", "ready": true}},
+    {"metadata": {"name": "db-1", "labels": {"app": "db"}},
+     "spec": {"replicas": 1, "containers": [{"name": "postgres"}]},
+     "status": {"phase": "Pending", "ready": false, "message": "a==b"}},
+    {"metadata": {"name": "job-1"}, "status": {"phase": "Succeeded", "ready": null}}
+  ]
+}`
+
+func TestEvalQuery(t *testing.T) {
+	root, err := parseDocument([]byte(queryDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"empty", "", []string{strings.TrimSuffix(root.text(formatJSON, false), "\n")}},
+		{"path", ".kind", []string{"List"}},
+		{"braces", "{.items[0].metadata.name}", []string{"web-1"}},
+		{"dollar", "$.items[1].metadata.name", []string{"db-1"}},
+		{"wildcard", ".items[*].metadata.name", []string{"web-1", "db-1", "job-1"}},
+		{"jq iterate", ".items[].metadata.name", []string{"web-1", "db-1", "job-1"}},
+		{"negative index", ".items[-1].metadata.name", []string{"job-1"}},
+		{"out of range", ".items[7].metadata.name", nil},
+		{"quoted member", `.items[0].metadata.labels['app']`, []string{"web"}},
+		{"missing field", ".items[0].spec.nodeName", nil},
+		{"recursive", "..name", []string{"web-1", "nginx", "envoy", "db-1", "postgres", "job-1"}},
+		{"recursive then path", ".items[0]..containers[0].name", []string{"nginx"}},
+		{"filter string", `.items[?(@.status.phase=="Running")].metadata.name`, []string{"web-1"}},
+		{"filter number", `.items[?(@.spec.replicas > 1)].metadata.name`, []string{"web-1"}},
+		{"filter less or equal", `.items[?(@.spec.replicas <= 1)].metadata.name`, []string{"db-1"}},
+		{"filter not equal", `.items[?(@.status.phase != "Running")].metadata.name`, []string{"db-1", "job-1"}},
+		{"filter exists", `.items[?(@.metadata.labels.tier)].metadata.name`, []string{"web-1"}},
+		{"filter truthy", `.items[?(@.status.ready)].metadata.name`, []string{"web-1"}},
+		{"filter quoted operator", `.items[?(@.status.message == "a==b")].metadata.name`, []string{"db-1"}},
+		{"filter operator in bracket", `.items[?(@.metadata.labels["a>b"] == "x")].metadata.name`, nil},
+		{"select", `.items[] | select(.metadata.labels.app == "db") | .metadata.name`, []string{"db-1"}},
+		{"select quoted pipe", `.items[] | select(.status.phase != "a|b") | .metadata.name`, []string{"web-1", "db-1", "job-1"}},
+		{"select container not null", `.items[] | select(.spec != null) | .metadata.name`, []string{"web-1", "db-1"}},
+		{"select container equals null", `.items[] | select(.spec == null) | .metadata.name`, nil},
+		{"select scalar null", `.items[] | select(.status.ready == null) | .metadata.name`, []string{"job-1"}},
+		{"length of array", ".items | length", []string{"3"}},
+		{"length of string", ".items[0].metadata.name | length", []string{"5"}},
+		{"length per item", ".items[].spec.containers | length", []string{"2", "1"}},
+		{"keys of object", ".items[0].metadata.labels | keys", []string{"[\n    \"app\",\n    \"tier\"\n]"}},
+		{"keys of array", ".items[0].spec.containers | keys", []string{"[\n    0,\n    1\n]"}},
+	}
+	for _, tt := range tests {
+		nodes, err := evalQuery(root, tt.query)
+		if err != nil {
+			t.Errorf("%s: evalQuery(%q): %v", tt.name, tt.query, err)
+			continue
+		}
+		var got []string
+		for _, n := range nodes {
+			got = append(got, queryResultText(n))
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: evalQuery(%q) = %q, want %q", tt.name, tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestEvalQueryErrors(t *testing.T) {
+	root, err := parseDocument([]byte(queryDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		query string
+		err   string
+	}{
+		{".items[0", "unclosed ["},
+		{".items[abc]", "unsupported subscript"},
+		{"..", "expected a field name"},
+		{"items", "unexpected"},
+		{`.items[?(@.status.phase == Running)]`, "quote strings"},
+		{`.items[] | select(.status.phase == Running)`, "quote strings"},
+		{`.items['unterminated]`, "unclosed ["},
+	}
+	for _, tt := range tests {
+		_, err := evalQuery(root, tt.query)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("evalQuery(%q) error = %v, want %q", tt.query, err, tt.err)
+		}
+	}
+}
+
+func TestSplitTopLevel(t *testing.T) {
+	tests := []struct {
+		s    string
+		want []string
+	}{
+		{".a | .b", []string{".a ", " .b"}},
+		{`select(.a == "x|y") | .b`, []string{`select(.a == "x|y") `, " .b"}},
+		{".a[?(@.b|c)] | length", []string{".a[?(@.b|c)] ", " length"}},
+		{".a", []string{".a"}},
+	}
+	for _, tt := range tests {
+		if got := splitTopLevel(tt.s, '|'); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitTopLevel(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,352 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// keyAction names something the user can trigger from the global key handler.
+type keyAction string
+
+const (
+	actionBack          keyAction = "back"
+	actionQuit          keyAction = "quit"
+	actionRefresh       keyAction = "refresh"
+	actionHistory       keyAction = "history"
+	actionCustomCommand keyAction = "custom_command"
+	actionWatch         keyAction = "watch"
+	actionLiveWatch     keyAction = "live_watch"
+	actionPalette       keyAction = "palette"
+	actionFavorite      keyAction = "favorite"
+	actionFavoriteUp    keyAction = "favorite_up"
+	actionFavoriteDown  keyAction = "favorite_down"
+	actionFavoriteScope keyAction = "favorite_scope"
+	actionHelp          keyAction = "help"
+	actionFocusNext     keyAction = "focus_next"
+	actionFocusPrev     keyAction = "focus_prev"
+	actionPaneGrow      keyAction = "pane_grow"
+	actionPaneShrink    keyAction = "pane_shrink"
+	actionZoom          keyAction = "zoom"
+	actionLayout        keyAction = "layout"
+	actionNotifications keyAction = "notifications"
+	actionLogin         keyAction = "login"
+	actionExplain       keyAction = "explain"
+	actionQuery         keyAction = "query"
+	actionFilter        keyAction = "filter"
+	actionClearFilter   keyAction = "clear_filter"
+)
+
+// defaultKeys maps every action to its default keys, separated by spaces.
+var defaultKeys = map[keyAction]string{
+	actionBack:          "Esc",
+	actionQuit:          "Ctrl+C",
+	actionRefresh:       "Ctrl+R",
+	actionHistory:       "Ctrl+Y",
+	actionCustomCommand: "Ctrl+X",
+	actionWatch:         "Ctrl+W",
+	actionLiveWatch:     "Ctrl+L",
+	actionPalette:       "Ctrl+P :",
+	actionFavorite:      "*",
+	actionFavoriteUp:    "[",
+	actionFavoriteDown:  "]",
+	actionFavoriteScope: "@",
+	actionHelp:          "? F1",
+	actionFocusNext:     "Tab",
+	actionFocusPrev:     "Backtab",
+	actionPaneGrow:      "+",
+	actionPaneShrink:    "-",
+	actionZoom:          "z",
+	actionLayout:        "Ctrl+T",
+	actionNotifications: "Ctrl+N",
+	actionLogin:         "Ctrl+O",
+	actionExplain:       "Ctrl+E",
+	actionQuery:         "|",
+	actionFilter:        "Ctrl+S",
+	actionClearFilter:   "Ctrl+K",
+}
+
+// reservedKeys are used by the menu and tables themselves and cannot be bound.
+// Ctrl+F and Ctrl+B page through tables and the output pane.
+var reservedKeys = []string{"Enter", "Up", "Down", "PgUp", "PgDn", "Home", "End", "Ctrl+F", "Ctrl+B",
+	"1", "2", "3", "4", "5", "6", "7", "8", "9"}
+
+// keyAliases are the Ctrl combinations that terminals send as the same code
+// as another key, so that they cannot be told apart.
+var keyAliases = map[tcell.Key]string{
+	tcell.KeyCtrlH:      "Backspace",
+	tcell.KeyCtrlI:      "Tab",
+	tcell.KeyCtrlM:      "Enter",
+	tcell.KeyCtrlLeftSq: "Esc",
+}
+
+// keyBinding is a single key, optionally with modifiers.
+type keyBinding struct {
+	key  tcell.Key
+	r    rune
+	mods tcell.ModMask
+}
+
+// parseKey parses a key description such as "Ctrl+X", "Alt+j", "F5", "Esc" or "*".
+func parseKey(s string) (keyBinding, error) {
+	var mods tcell.ModMask
+	base := s
+	for {
+		i := strings.Index(base, "+")
+		if i <= 0 || i == len(base)-1 {
+			break
+		}
+		switch strings.ToLower(base[:i]) {
+		case "ctrl":
+			mods |= tcell.ModCtrl
+		case "alt":
+			mods |= tcell.ModAlt
+		case "shift":
+			mods |= tcell.ModShift
+		default:
+			return keyBinding{}, fmt.Errorf("unknown modifier %q in %q", base[:i], s)
+		}
+		base = base[i+1:]
+	}
+
+	if runes := []rune(base); len(runes) == 1 {
+		r := runes[0]
+		if mods&tcell.ModCtrl != 0 {
+			lower := unicode.ToLower(r)
+			key := tcell.KeyCtrlA + tcell.Key(lower-'a')
+			if r == '[' {
+				key = tcell.KeyCtrlLeftSq
+			} else if lower < 'a' || lower > 'z' {
+				return keyBinding{}, fmt.Errorf("%q: only letters can be combined with Ctrl", s)
+			}
+			if alias, ok := keyAliases[key]; ok {
+				return keyBinding{}, fmt.Errorf("%q is the same key as %s in a terminal", s, alias)
+			}
+			return keyBinding{key: key}, nil
+		}
+		return keyBinding{key: tcell.KeyRune, r: r, mods: mods &^ tcell.ModShift}, nil
+	}
+
+	switch strings.ToLower(base) {
+	case "escape":
+		base = "Esc"
+	case "space":
+		return keyBinding{key: tcell.KeyRune, r: ' ', mods: mods}, nil
+	}
+	for key, name := range tcell.KeyNames {
+		if strings.EqualFold(name, base) {
+			return keyBinding{key: key, mods: mods}, nil
+		}
+	}
+	return keyBinding{}, fmt.Errorf("unknown key %q", s)
+}
+
+// matches reports whether event is this key binding.
+func (b keyBinding) matches(event *tcell.EventKey) bool {
+	if event.Key() != b.key {
+		return false
+	}
+	if b.key == tcell.KeyRune {
+		return event.Rune() == b.r && event.Modifiers()&tcell.ModAlt == b.mods&tcell.ModAlt
+	}
+	if b.key >= tcell.KeyCtrlA && b.key <= tcell.KeyCtrlZ {
+		return true
+	}
+	const checked = tcell.ModAlt | tcell.ModShift | tcell.ModCtrl
+	return event.Modifiers()&checked == b.mods&checked
+}
+
+// String renders the binding the way it is written in the config.
+func (b keyBinding) String() string {
+	var prefix string
+	if b.mods&tcell.ModCtrl != 0 {
+		prefix += "Ctrl+"
+	}
+	if b.mods&tcell.ModAlt != 0 {
+		prefix += "Alt+"
+	}
+	if b.mods&tcell.ModShift != 0 {
+		prefix += "Shift+"
+	}
+
+	switch {
+	case b.key == tcell.KeyRune && b.r == ' ':
+		return prefix + "Space"
+	case b.key == tcell.KeyRune:
+		return prefix + string(b.r)
+	case keyAliases[b.key] != "":
+		return prefix + keyAliases[b.key]
+	case b.key >= tcell.KeyCtrlA && b.key <= tcell.KeyCtrlZ:
+		return fmt.Sprintf("Ctrl+%c", 'A'+rune(b.key-tcell.KeyCtrlA))
+	}
+	return prefix + tcell.KeyNames[b.key]
+}
+
+// Keymap resolves key events to actions.
+type Keymap struct {
+	bindings map[keyAction][]keyBinding
+	vim      bool
+}
+
+// buildKeymap applies user overrides on top of the default keys. Invalid or
+// conflicting bindings are reported as problems; an action whose override is
+// invalid keeps its default keys. With vim set the vi navigation keys are
+// reserved as well.
+func buildKeymap(overrides map[string]string, vim bool) (*Keymap, []string) {
+	var problems []string
+	keys := make(map[keyAction]string, len(defaultKeys))
+	for action, spec := range defaultKeys {
+		keys[action] = spec
+	}
+
+	for name, spec := range overrides {
+		action := keyAction(name)
+		if _, ok := defaultKeys[action]; !ok {
+			problems = append(problems, fmt.Sprintf("unknown action %q", name))
+			continue
+		}
+		keys[action] = spec
+	}
+
+	keymap := &Keymap{bindings: make(map[keyAction][]keyBinding), vim: vim}
+	owners := make(map[keyBinding]keyAction)
+	for _, reserved := range reservedKeys {
+		binding, _ := parseKey(reserved)
+		owners[binding] = "menu navigation"
+	}
+	if vim {
+		for _, reserved := range vimKeys {
+			binding, _ := parseKey(reserved)
+			owners[binding] = "vi navigation"
+		}
+	}
+
+	actions := make([]keyAction, 0, len(keys))
+	for action := range keys {
+		actions = append(actions, action)
+	}
+	sort.Slice(actions, func(i, j int) bool { return actions[i] < actions[j] })
+
+	for _, action := range actions {
+		bindings, err := parseKeys(keys[action])
+		if err != nil && keys[action] != defaultKeys[action] {
+			problems = append(problems, fmt.Sprintf("%s: %v, using %s", action, err, defaultKeys[action]))
+			bindings, err = parseKeys(defaultKeys[action])
+		}
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", action, err))
+			continue
+		}
+
+		for _, binding := range bindings {
+			if owner, taken := owners[binding]; taken {
+				problems = append(problems, fmt.Sprintf("%s: %s is already bound to %s", action, binding, owner))
+				continue
+			}
+			owners[binding] = action
+			keymap.bindings[action] = append(keymap.bindings[action], binding)
+		}
+	}
+	return keymap, problems
+}
+
+func parseKeys(spec string) ([]keyBinding, error) {
+	fields := strings.Fields(spec)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no key given")
+	}
+	bindings := make([]keyBinding, 0, len(fields))
+	for _, field := range fields {
+		binding, err := parseKey(field)
+		if err != nil {
+			return nil, err
+		}
+		bindings = append(bindings, binding)
+	}
+	return bindings, nil
+}
+
+// action returns the action bound to event, if any.
+func (k *Keymap) action(event *tcell.EventKey) (keyAction, bool) {
+	for action, bindings := range k.bindings {
+		for _, binding := range bindings {
+			if binding.matches(event) {
+				return action, true
+			}
+		}
+	}
+	return "", false
+}
+
+// is reports whether event is bound to action.
+func (k *Keymap) is(event *tcell.EventKey, action keyAction) bool {
+	for _, binding := range k.bindings[action] {
+		if binding.matches(event) {
+			return true
+		}
+	}
+	return false
+}
+
+// label returns the keys bound to action for display, e.g. "Ctrl+P/:".
+func (k *Keymap) label(action keyAction) string {
+	names := make([]string, 0, len(k.bindings[action]))
+	for _, binding := range k.bindings[action] {
+		names = append(names, binding.String())
+	}
+	return strings.Join(names, "/")
+}
+
+// checkMenuShortcuts reports shortcuts from the menu that are shadowed by a
+// global key or used twice within the same menu.
+func (k *Keymap) checkMenuShortcuts(menu []*MenuItem, trail string) []string {
+	var problems []string
+	seen := make(map[string]string)
+	for _, item := range menu {
+		path := strings.TrimPrefix(trail+"/"+item.Name, "/")
+		if item.Shortcut != "" {
+			event := tcell.NewEventKey(tcell.KeyRune, []rune(item.Shortcut)[0], tcell.ModNone)
+			if action, ok := k.action(event); ok {
+				problems = append(problems, fmt.Sprintf("shortcut %q of %s is shadowed by %s", item.Shortcut, path, action))
+			}
+			if k.vim && isVimKey(item.Shortcut) {
+				problems = append(problems, fmt.Sprintf("shortcut %q of %s is shadowed by vi navigation", item.Shortcut, path))
+			}
+			if other, dup := seen[item.Shortcut]; dup {
+				problems = append(problems, fmt.Sprintf("shortcut %q of %s is also used by %s", item.Shortcut, path, other))
+			}
+			seen[item.Shortcut] = path
+		}
+		problems = append(problems, k.checkMenuShortcuts(item.Submenu, path)...)
+	}
+	return problems
+}
+
+// loadKeymap builds the keymap from the user config and returns any problems
+// found in the configured bindings.
+func (nav *OCNavigator) loadKeymap() []string {
+	keymap, problems := buildKeymap(nav.config.Keys, nav.config.VimKeys)
+	nav.keymap = keymap
+	return problems
+}
+
+// showKeymapProblems lists invalid or conflicting key bindings at startup.
+func (nav *OCNavigator) showKeymapProblems(problems []string) {
+	modal := tview.NewModal().
+		SetText("Problems in key bindings:\n\n" + strings.Join(problems, "\n")).
+		AddButtons([]string{"OK"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			nav.closeOverlay()
+		})
+	nav.showOverlay(modal)
+}
+
+// keyLabel returns the keys bound to action, escaped and styled for a
+// dynamic-color text view.
+func (nav *OCNavigator) keyLabel(action keyAction) string {
+	return nav.theme.paint(roleKey, tview.Escape(nav.keymap.label(action)))
+}
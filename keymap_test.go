@@ -0,0 +1,127 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		spec string
+		want keyBinding
+		err  string
+	}{
+		{spec: "Ctrl+X", want: keyBinding{key: tcell.KeyCtrlX}},
+		{spec: "ctrl+x", want: keyBinding{key: tcell.KeyCtrlX}},
+		{spec: "Alt+j", want: keyBinding{key: tcell.KeyRune, r: 'j', mods: tcell.ModAlt}},
+		{spec: "*", want: keyBinding{key: tcell.KeyRune, r: '*'}},
+		{spec: "+", want: keyBinding{key: tcell.KeyRune, r: '+'}},
+		{spec: "Shift+G", want: keyBinding{key: tcell.KeyRune, r: 'G'}},
+		{spec: "F5", want: keyBinding{key: tcell.KeyF5}},
+		{spec: "Esc", want: keyBinding{key: tcell.KeyEscape}},
+		{spec: "Escape", want: keyBinding{key: tcell.KeyEscape}},
+		{spec: "Tab", want: keyBinding{key: tcell.KeyTab}},
+		{spec: "Space", want: keyBinding{key: tcell.KeyRune, r: ' '}},
+		{spec: "Ctrl+1", err: "only letters"},
+		{spec: "Hyper+x", err: "unknown modifier"},
+		{spec: "Nope", err: "unknown key"},
+		{spec: "Ctrl+H", err: "same key as Backspace"},
+		{spec: "Ctrl+I", err: "same key as Tab"},
+		{spec: "Ctrl+M", err: "same key as Enter"},
+		{spec: "Ctrl+[", err: "same key as Esc"},
+	}
+	for _, tt := range tests {
+		got, err := parseKey(tt.spec)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("parseKey(%q) error = %v, want %q", tt.spec, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseKey(%q) = %+v, %v, want %+v", tt.spec, got, err, tt.want)
+		}
+	}
+}
+
+func TestKeyBindingString(t *testing.T) {
+	for _, spec := range []string{"Ctrl+X", "Alt+j", "*", "F5", "Esc", "Tab", "Enter", "Backspace", "Space"} {
+		binding, err := parseKey(spec)
+		if err != nil {
+			t.Fatalf("parseKey(%q): %v", spec, err)
+		}
+		if got := binding.String(); got != spec {
+			t.Errorf("parseKey(%q).String() = %q", spec, got)
+		}
+	}
+}
+
+func TestBuildKeymap(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string]string
+		vim       bool
+		action    keyAction
+		label     string
+		problems  []string
+	}{
+		{name: "defaults", action: actionHistory, label: "Ctrl+Y"},
+		{name: "override", overrides: map[string]string{"history": "Ctrl+G F2"}, action: actionHistory, label: "Ctrl+G/F2"},
+		{name: "unknown action", overrides: map[string]string{"fly": "Ctrl+G"}, problems: []string{`unknown action "fly"`}},
+		{name: "invalid key keeps default", overrides: map[string]string{"history": "Ctrl+H"},
+			action: actionHistory, label: "Ctrl+Y", problems: []string{"same key as Backspace"}},
+		// Actions are bound in name order, so watch loses its key to history
+		{name: "conflict", overrides: map[string]string{"history": "Ctrl+W"},
+			action: actionWatch, label: "", problems: []string{"watch: Ctrl+W is already bound to history"}},
+		{name: "reserved", overrides: map[string]string{"zoom": "Enter"},
+			action: actionZoom, label: "", problems: []string{"Enter is already bound to menu navigation"}},
+		{name: "paging reserved", overrides: map[string]string{"filter": "Ctrl+F"},
+			action: actionFilter, label: "", problems: []string{"Ctrl+F is already bound to menu navigation"}},
+		{name: "vim reserved", overrides: map[string]string{"zoom": "j"}, vim: true,
+			action: actionZoom, label: "", problems: []string{"j is already bound to vi navigation"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keymap, problems := buildKeymap(tt.overrides, tt.vim)
+			if tt.action != "" {
+				if got := keymap.label(tt.action); got != tt.label {
+					t.Errorf("label(%s) = %q, want %q", tt.action, got, tt.label)
+				}
+			}
+			if len(problems) != len(tt.problems) {
+				t.Fatalf("problems = %q, want %q", problems, tt.problems)
+			}
+			for i, want := range tt.problems {
+				if !strings.Contains(problems[i], want) {
+					t.Errorf("problem %d = %q, want it to contain %q", i, problems[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestKeymapAction(t *testing.T) {
+	keymap, problems := buildKeymap(nil, false)
+	if len(problems) > 0 {
+		t.Fatalf("default keys have problems: %q", problems)
+	}
+	tests := []struct {
+		event  *tcell.EventKey
+		action keyAction
+		ok     bool
+	}{
+		{tcell.NewEventKey(tcell.KeyCtrlY, 0, tcell.ModCtrl), actionHistory, true},
+		{tcell.NewEventKey(tcell.KeyBackspace, 0, tcell.ModNone), "", false},
+		{tcell.NewEventKey(tcell.KeyBackspace2, 0, tcell.ModNone), "", false},
+		{tcell.NewEventKey(tcell.KeyRune, '|', tcell.ModNone), actionQuery, true},
+		{tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModNone), "", false},
+	}
+	for _, tt := range tests {
+		action, ok := keymap.action(tt.event)
+		if action != tt.action || ok != tt.ok {
+			t.Errorf("action(%s) = %q, %v, want %q, %v", tt.event.Name(), action, ok, tt.action, tt.ok)
+		}
+	}
+}
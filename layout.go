@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	defaultMenuPercent   = 33
+	defaultDetailPercent = 50
+	minPanePercent       = 10
+	maxPanePercent       = 90
+	panePercentStep      = 5
+
+	// Terminals narrower than this stack the panes vertically in auto mode
+	stackedWidth = 100
+)
+
+// Layout modes: "auto" stacks the panes on narrow terminals only.
+const (
+	layoutAuto       = "auto"
+	layoutStacked    = "stacked"
+	layoutSideBySide = "side-by-side"
+)
+
+// LayoutConfig holds the pane proportions and arrangement saved between sessions.
+type LayoutConfig struct {
+	MenuPercent   int    `json:"menu_percent,omitempty"`
+	DetailPercent int    `json:"detail_percent,omitempty"`
+	Mode          string `json:"mode,omitempty"`
+}
+
+func (l LayoutConfig) menuPercent() int {
+	if l.MenuPercent == 0 {
+		return defaultMenuPercent
+	}
+	return l.MenuPercent
+}
+
+func (l LayoutConfig) detailPercent() int {
+	if l.DetailPercent == 0 {
+		return defaultDetailPercent
+	}
+	return l.DetailPercent
+}
+
+func (l LayoutConfig) mode() string {
+	switch l.Mode {
+	case layoutStacked, layoutSideBySide:
+		return l.Mode
+	}
+	return layoutAuto
+}
+
+// applyLayout arranges the panes in mainFlex according to the layout config,
+// the terminal width and the zoomed pane, if any.
+func (nav *OCNavigator) applyLayout() {
+	layout := nav.config.Layout
+	nav.mainFlex.Clear()
+
+	if nav.zoomed != nil {
+		nav.mainFlex.SetDirection(tview.FlexColumn).AddItem(nav.zoomed, 0, 1, true)
+		return
+	}
+
+	nav.rightPanel.Clear().
+		AddItem(nav.detailView, 0, layout.detailPercent(), false).
+		AddItem(nav.outputPages, 0, 100-layout.detailPercent(), false)
+
+	direction := tview.FlexColumn
+	if nav.stacked {
+		direction = tview.FlexRow
+	}
+	nav.mainFlex.SetDirection(direction).
+		AddItem(nav.leftPanel, 0, layout.menuPercent(), true).
+		AddItem(nav.rightPanel, 0, 100-layout.menuPercent(), false)
+}
+
+// updateStacking records the screen and its width before every draw so that
+// the auto layout can follow it and the clipboard can be reached. It never
+// asks for a redraw itself.
+func (nav *OCNavigator) updateStacking(screen tcell.Screen) bool {
+	nav.screen = screen
+	nav.screenWidth, _ = screen.Size()
+	nav.refreshStacking()
+	return false
+}
+
+// refreshStacking switches between the side-by-side and stacked arrangement
+// when the layout mode or the terminal width asks for a different one.
+func (nav *OCNavigator) refreshStacking() {
+	stacked := false
+	switch nav.config.Layout.mode() {
+	case layoutStacked:
+		stacked = true
+	case layoutAuto:
+		stacked = nav.screenWidth > 0 && nav.screenWidth < stackedWidth
+	}
+
+	if stacked != nav.stacked {
+		nav.stacked = stacked
+		nav.applyLayout()
+	}
+}
+
+// focusedPane returns the pane of the main layout that has or contains focus.
+func (nav *OCNavigator) focusedPane() tview.Primitive {
+	switch nav.app.GetFocus() {
+	case nav.detailView:
+		return nav.detailView
+	case nav.commandView, nav.resourceTable, nav.documentTree:
+		return nav.outputPages
+	}
+	return nav.leftPanel
+}
+
+// resizeFocusedPane grows (1) or shrinks (-1) the focused pane and saves the
+// new proportions.
+func (nav *OCNavigator) resizeFocusedPane(direction int) {
+	layout := &nav.config.Layout
+	step := direction * panePercentStep
+
+	switch nav.focusedPane() {
+	case nav.leftPanel:
+		layout.MenuPercent = clampPercent(layout.menuPercent() + step)
+	case nav.detailView:
+		layout.DetailPercent = clampPercent(layout.detailPercent() + step)
+	case nav.outputPages:
+		layout.DetailPercent = clampPercent(layout.detailPercent() - step)
+	}
+
+	nav.applyLayout()
+	nav.saveConfig()
+}
+
+func clampPercent(percent int) int {
+	return min(max(percent, minPanePercent), maxPanePercent)
+}
+
+// toggleZoom shows the focused pane alone, or restores all panes.
+func (nav *OCNavigator) toggleZoom() {
+	if nav.zoomed != nil {
+		nav.zoomed = nil
+	} else {
+		nav.zoomed = nav.focusedPane()
+	}
+	nav.applyLayout()
+}
+
+// cycleLayoutMode switches between the auto, stacked and side-by-side
+// arrangements and saves the choice.
+func (nav *OCNavigator) cycleLayoutMode() {
+	layout := &nav.config.Layout
+	switch layout.mode() {
+	case layoutAuto:
+		layout.Mode = layoutStacked
+	case layoutStacked:
+		layout.Mode = layoutSideBySide
+	default:
+		layout.Mode = layoutAuto
+	}
+
+	nav.refreshStacking()
+	nav.saveConfig()
+	nav.notify(notifyInfo, fmt.Sprintf("Layout: %s", layout.mode()))
+}
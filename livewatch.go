@@ -0,0 +1,421 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	liveWatchMinBackoff = time.Second
+	liveWatchMaxBackoff = 30 * time.Second
+)
+
+// watchEvent is a single event emitted by "oc get -w -o json --output-watch-events".
+type watchEvent struct {
+	Type   string                 `json:"type"`
+	Object map[string]interface{} `json:"object"`
+}
+
+// liveWatch is a long-running "oc get -w" stream feeding the resource table.
+type liveWatch struct {
+	command       string
+	args          []string
+	showNamespace bool
+	cancel        context.CancelFunc
+	connected     bool
+	keys          []string
+	objects       map[string]map[string]interface{}
+	changed       map[string]bool // objects modified since they were listed
+
+	// kind is the resource the column sets are looked up for, and columns
+	// the set shown instead of status and age, if any.
+	kind    string
+	columns *ColumnSet
+}
+
+// liveWatchArgs turns a list command such as "oc get pods -o wide" into the
+// arguments for a JSON watch stream, dropping any output format flags.
+func liveWatchArgs(command string) []string {
+	fields := strings.Fields(command)
+	args := make([]string, 0, len(fields)+4)
+	for i := 1; i < len(fields); i++ {
+		field := fields[i]
+		switch {
+		case field == "-o" || field == "--output":
+			i++
+		case strings.HasPrefix(field, "-o") || strings.HasPrefix(field, "--output="):
+		case field == "-w" || field == "--watch":
+		default:
+			args = append(args, field)
+		}
+	}
+	return append(args, "-w", "-o", "json", "--output-watch-events")
+}
+
+// toggleLiveWatch starts a live stream for the last list command, or stops
+// the running one.
+func (nav *OCNavigator) toggleLiveWatch() {
+	if nav.live != nil {
+		nav.stopLiveWatch()
+		nav.notify(notifyInfo, "Live watch stopped")
+		return
+	}
+
+	if nav.lastListCommand == "" {
+		nav.notify(notifyWarn, "Nothing to watch: run a list command first")
+		return
+	}
+
+	nav.startLiveWatch(nav.lastListCommand)
+}
+
+// startLiveWatch switches the output pane to the resource table and keeps it
+// in sync with an "oc get -w" stream until stopLiveWatch is called.
+func (nav *OCNavigator) startLiveWatch(command string) {
+	nav.stopWatch()
+	nav.closeDocument()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lw := &liveWatch{
+		command:       command,
+		args:          liveWatchArgs(command),
+		showNamespace: strings.Contains(command, " -A") || strings.Contains(command, "--all-namespaces"),
+		cancel:        cancel,
+		objects:       make(map[string]map[string]interface{}),
+		changed:       make(map[string]bool),
+		kind:          nav.columnKind(command),
+	}
+	nav.live = lw
+
+	nav.resetResourceTable(lw)
+	nav.outputPages.SwitchToPage("table")
+	nav.app.SetFocus(nav.resourceTable)
+	nav.updateStatusBar()
+	nav.updateBreadcrumb()
+
+	go nav.runLiveWatch(ctx, lw)
+}
+
+// stopLiveWatch terminates the stream and returns the output pane to plain text.
+func (nav *OCNavigator) stopLiveWatch() {
+	if nav.live == nil {
+		return
+	}
+	nav.live.cancel()
+	nav.live = nil
+
+	nav.outputPages.SwitchToPage("text")
+	nav.app.SetFocus(nav.menuList)
+	nav.updateStatusBar()
+	nav.updateBreadcrumb()
+}
+
+// runLiveWatch keeps an "oc get -w" process running, reconnecting with
+// exponential backoff whenever the stream drops.
+func (nav *OCNavigator) runLiveWatch(ctx context.Context, lw *liveWatch) {
+	backoff := liveWatchMinBackoff
+
+	for {
+		received, err := nav.streamWatchEvents(ctx, lw)
+		if ctx.Err() != nil {
+			return
+		}
+		if received {
+			backoff = liveWatchMinBackoff
+		}
+
+		delay := backoff
+		nav.app.QueueUpdateDraw(func() {
+			if nav.live != lw {
+				return
+			}
+			lw.connected = false
+			nav.updateStatusBar()
+			if err != nil {
+				nav.notify(notifyWarn, fmt.Sprintf("Watch stream dropped (%v), reconnecting in %s", err, delay))
+			} else {
+				nav.notify(notifyWarn, fmt.Sprintf("Watch stream closed, reconnecting in %s", delay))
+			}
+		})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		backoff *= 2
+		if backoff > liveWatchMaxBackoff {
+			backoff = liveWatchMaxBackoff
+		}
+	}
+}
+
+// streamWatchEvents runs a single watch process and applies its events to the
+// table until the process exits. It reports whether any event was received.
+func (nav *OCNavigator) streamWatchEvents(ctx context.Context, lw *liveWatch) (bool, error) {
+	cmd, err := nav.cli.command(ctx, lw.args...)
+	if err != nil {
+		return false, err
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return false, err
+	}
+	if err := cmd.Start(); err != nil {
+		return false, err
+	}
+
+	// A fresh stream starts by re-listing every object as ADDED, so drop
+	// whatever the previous connection left behind.
+	nav.app.QueueUpdateDraw(func() {
+		if nav.live == lw {
+			lw.connected = true
+			nav.resetResourceTable(lw)
+			nav.updateStatusBar()
+		}
+	})
+
+	received := false
+	decoder := json.NewDecoder(stdout)
+	for {
+		var event watchEvent
+		if err := decoder.Decode(&event); err != nil {
+			break
+		}
+		received = true
+		nav.app.QueueUpdateDraw(func() {
+			if nav.live == lw {
+				nav.applyWatchEvent(lw, event)
+			}
+		})
+	}
+
+	if err := cmd.Wait(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return received, fmt.Errorf("%s", msg)
+		}
+		return received, err
+	}
+	return received, nil
+}
+
+// resetResourceTable clears the table and writes the header row for lw.
+func (nav *OCNavigator) resetResourceTable(lw *liveWatch) {
+	lw.keys = lw.keys[:0]
+	lw.objects = make(map[string]map[string]interface{})
+	lw.changed = make(map[string]bool)
+	nav.renderResourceTable(lw)
+}
+
+// renderResourceTable writes the header row and a row for every object of lw.
+func (nav *OCNavigator) renderResourceTable(lw *liveWatch) {
+	nav.resourceTable.Clear()
+	for column, header := range lw.headers() {
+		nav.resourceTable.SetCell(0, column, tview.NewTableCell(header).
+			SetStyle(nav.theme.style(roleTitle)).
+			SetSelectable(false))
+	}
+	for i, key := range lw.keys {
+		nav.setResourceRow(lw, i+1, key)
+	}
+	nav.updateResourceTableTitle(lw)
+}
+
+// setResourceRow writes the cells of the object under key to row, marking
+// objects that were modified.
+func (nav *OCNavigator) setResourceRow(lw *liveWatch, row int, key string) {
+	for column, value := range lw.row(lw.objects[key]) {
+		cell := tview.NewTableCell(value)
+		if lw.changed[key] {
+			cell.SetStyle(nav.theme.style(roleWarning))
+		}
+		nav.resourceTable.SetCell(row, column, cell)
+	}
+}
+
+// switchColumnSet shows the next column set of the kind in the live table,
+// going back to status and age after the last one.
+func (nav *OCNavigator) switchColumnSet() {
+	lw := nav.live
+	sets := nav.columnSets(lw.kind)
+	if len(sets) == 0 {
+		nav.notify(notifyWarn, fmt.Sprintf("No column sets for %s: add one with the query box or under \"columns\" in the config", lw.command))
+		return
+	}
+
+	next := 0
+	if lw.columns != nil {
+		next = len(sets)
+		for i := range sets {
+			if sets[i].Name == lw.columns.Name {
+				next = i + 1
+			}
+		}
+	}
+	lw.columns = nil
+	name := "default"
+	if next < len(sets) {
+		lw.columns = &sets[next]
+		name = sets[next].Name
+	}
+
+	row, _ := nav.resourceTable.GetSelection()
+	nav.renderResourceTable(lw)
+	nav.resourceTable.Select(row, 0)
+	nav.notify(notifyInfo, fmt.Sprintf("Showing %s columns", name))
+}
+
+// handleTableKeys switches the column set of the live table with c.
+func (nav *OCNavigator) handleTableKeys(event *tcell.EventKey) *tcell.EventKey {
+	if nav.live != nil && event.Key() == tcell.KeyRune && event.Rune() == 'c' {
+		nav.switchColumnSet()
+		return nil
+	}
+	return event
+}
+
+// applyWatchEvent inserts, updates or removes the table row for a single event.
+func (nav *OCNavigator) applyWatchEvent(lw *liveWatch, event watchEvent) {
+	if event.Object == nil {
+		return
+	}
+
+	key := objectKey(event.Object)
+	index := sort.SearchStrings(lw.keys, key)
+	exists := index < len(lw.keys) && lw.keys[index] == key
+
+	switch event.Type {
+	case "ADDED", "MODIFIED":
+		if !exists {
+			lw.keys = append(lw.keys, "")
+			copy(lw.keys[index+1:], lw.keys[index:])
+			lw.keys[index] = key
+			nav.resourceTable.InsertRow(index + 1)
+		}
+		lw.objects[key] = event.Object
+		lw.changed[key] = exists
+		nav.setResourceRow(lw, index+1, key)
+	case "DELETED":
+		if exists {
+			lw.keys = append(lw.keys[:index], lw.keys[index+1:]...)
+			delete(lw.objects, key)
+			delete(lw.changed, key)
+			nav.resourceTable.RemoveRow(index + 1)
+		}
+	}
+
+	nav.updateResourceTableTitle(lw)
+}
+
+func (nav *OCNavigator) updateResourceTableTitle(lw *liveWatch) {
+	title := fmt.Sprintf(" Live: %s (%d items) ", lw.command, len(lw.keys))
+	if lw.columns != nil {
+		title += fmt.Sprintf("[%s columns] ", lw.columns.Name)
+	}
+	nav.resourceTable.SetTitle(title)
+}
+
+func (lw *liveWatch) headers() []string {
+	headers := []string{"NAME", "STATUS", "AGE"}
+	if lw.columns != nil {
+		headers = []string{"NAME"}
+		for _, column := range lw.columns.Columns {
+			headers = append(headers, column.Header)
+		}
+	}
+	if lw.showNamespace {
+		headers = append([]string{"NAMESPACE"}, headers...)
+	}
+	return headers
+}
+
+func (lw *liveWatch) row(obj map[string]interface{}) []string {
+	row := []string{
+		nestedString(obj, "metadata", "name"),
+		objectStatus(obj),
+		formatAge(nestedString(obj, "metadata", "creationTimestamp")),
+	}
+	if lw.columns != nil {
+		row = row[:1]
+		doc := valueNode(obj)
+		for _, column := range lw.columns.Columns {
+			row = append(row, columnValue(doc, column.Path))
+		}
+	}
+	if lw.showNamespace {
+		row = append([]string{nestedString(obj, "metadata", "namespace")}, row...)
+	}
+	return row
+}
+
+// objectKey identifies an object by namespace and name.
+func objectKey(obj map[string]interface{}) string {
+	return nestedString(obj, "metadata", "namespace") + "/" + nestedString(obj, "metadata", "name")
+}
+
+// objectStatus derives a short status for any kind of object: the phase when
+// there is one, otherwise the first well-known condition that is true.
+func objectStatus(obj map[string]interface{}) string {
+	if phase := nestedString(obj, "status", "phase"); phase != "" {
+		return phase
+	}
+
+	status, _ := obj["status"].(map[string]interface{})
+	conditions, _ := status["conditions"].([]interface{})
+	for _, wanted := range []string{"Ready", "Available", "Complete", "Failed"} {
+		for _, c := range conditions {
+			condition, _ := c.(map[string]interface{})
+			if condition["type"] == wanted && condition["status"] == "True" {
+				return wanted
+			}
+		}
+	}
+	return "-"
+}
+
+// nestedString walks obj along path and returns the string found there, or
+// an empty string if any step is missing.
+func nestedString(obj map[string]interface{}, path ...string) string {
+	var current interface{} = obj
+	for _, key := range path {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		current = m[key]
+	}
+	s, _ := current.(string)
+	return s
+}
+
+// formatAge renders an RFC 3339 timestamp as a short age like "5m" or "3d".
+func formatAge(timestamp string) string {
+	created, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return "-"
+	}
+
+	age := time.Since(created)
+	switch {
+	case age < 2*time.Minute:
+		return fmt.Sprintf("%ds", int(age.Seconds()))
+	case age < 2*time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(age.Hours()/24))
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+func TestLiveWatchArgs(t *testing.T) {
+	watch := []string{"-w", "-o", "json", "--output-watch-events"}
+	tests := []struct {
+		command string
+		want    []string
+	}{
+		{"oc get pods", []string{"get", "pods"}},
+		{"oc get pods -o wide", []string{"get", "pods"}},
+		{"oc get pods -owide", []string{"get", "pods"}},
+		{"oc get pods --output yaml", []string{"get", "pods"}},
+		{"oc get pods --output=json", []string{"get", "pods"}},
+		{"oc get pods -w", []string{"get", "pods"}},
+		{"oc get pods --watch -A", []string{"get", "pods", "-A"}},
+		{"oc get deploy -n demo -l app=web", []string{"get", "deploy", "-n", "demo", "-l", "app=web"}},
+	}
+	for _, tt := range tests {
+		want := append(tt.want, watch...)
+		if got := liveWatchArgs(tt.command); !reflect.DeepEqual(got, want) {
+			t.Errorf("liveWatchArgs(%q) = %q, want %q", tt.command, got, want)
+		}
+	}
+}
+
+func TestObjectStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  map[string]interface{}
+		want string
+	}{
+		{"phase", map[string]interface{}{"status": map[string]interface{}{"phase": "Running"}}, "Running"},
+		{
+			"condition",
+			map[string]interface{}{"status": map[string]interface{}{"conditions": []interface{}{
+				map[string]interface{}{"type": "Progressing", "status": "True"},
+				map[string]interface{}{"type": "Available", "status": "True"},
+			}}},
+			"Available",
+		},
+		{
+			"false condition",
+			map[string]interface{}{"status": map[string]interface{}{"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "False"},
+			}}},
+			"-",
+		},
+		{"no status", map[string]interface{}{}, "-"},
+	}
+	for _, tt := range tests {
+		if got := objectStatus(tt.obj); got != tt.want {
+			t.Errorf("%s: objectStatus() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		timestamp string
+		want      string
+	}{
+		{now.Add(-30 * time.Second).Format(time.RFC3339), "30s"},
+		{now.Add(-5 * time.Minute).Format(time.RFC3339), "5m"},
+		{now.Add(-3 * time.Hour).Format(time.RFC3339), "3h"},
+		{now.Add(-72 * time.Hour).Format(time.RFC3339), "3d"},
+		{"", "-"},
+		{"yesterday", "-"},
+	}
+	for _, tt := range tests {
+		if got := formatAge(tt.timestamp); got != tt.want {
+			t.Errorf("formatAge(%q) = %q, want %q", tt.timestamp, got, tt.want)
+		}
+	}
+}
+
+func TestRenderResourceTableKeepsChangedRows(t *testing.T) {
+	nav := &OCNavigator{resourceTable: tview.NewTable(), theme: darkTheme}
+	lw := &liveWatch{command: "oc get pods", kind: "pods"}
+	nav.resetResourceTable(lw)
+	pod := func(name, phase string) map[string]interface{} {
+		return map[string]interface{}{
+			"metadata": map[string]interface{}{"name": name, "namespace": "demo"},
+			"status":   map[string]interface{}{"phase": phase},
+		}
+	}
+	nav.applyWatchEvent(lw, watchEvent{Type: "ADDED", Object: pod("web-1", "Running")})
+	nav.applyWatchEvent(lw, watchEvent{Type: "ADDED", Object: pod("web-2", "Pending")})
+	nav.applyWatchEvent(lw, watchEvent{Type: "MODIFIED", Object: pod("web-2", "Running")})
+
+	check := func(when string) {
+		t.Helper()
+		if got := nav.resourceTable.GetCell(2, 1).Text; got != "Running" {
+			t.Fatalf("%s: web-2 status = %q, want Running", when, got)
+		}
+		changed := nav.theme.style(roleWarning)
+		if style := nav.resourceTable.GetCell(1, 0).Style; style == changed {
+			t.Errorf("%s: the added row is styled as changed", when)
+		}
+		if style := nav.resourceTable.GetCell(2, 0).Style; style != changed {
+			t.Errorf("%s: the modified row lost its style", when)
+		}
+	}
+	check("after the events")
+	lw.columns = &ColumnSet{Name: "wide", Columns: []Column{{Header: "PHASE", Path: ".status.phase"}}}
+	nav.renderResourceTable(lw)
+	check("after switching columns")
+
+	nav.applyWatchEvent(lw, watchEvent{Type: "DELETED", Object: pod("web-2", "Running")})
+	if lw.changed["demo/web-2"] {
+		t.Error("a deleted object is still marked as changed")
+	}
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// loginTimeout bounds an "oc login" or "oc logout" call.
+const loginTimeout = 2 * healthCheckTimeout
+
+// loginRequest is an "oc login" whose secrets stay off the command line,
+// where any local user could read them from the process list. A token is
+// handed over in a temporary kubeconfig, which oc login picks up as existing
+// credentials for the server, and a password answers the prompt of oc on
+// its standard input.
+type loginRequest struct {
+	args     []string
+	server   string
+	token    string
+	password string
+	insecure bool
+}
+
+// newLoginRequest builds the "oc login" for the dialog. A token wins over a
+// username and password when both are given.
+func newLoginRequest(server, token, username, password string, insecure bool) loginRequest {
+	req := loginRequest{args: []string{"login"}, server: server, insecure: insecure}
+	if server != "" {
+		req.args = append(req.args, server)
+	}
+	if token != "" {
+		req.token = token
+	} else {
+		req.args = append(req.args, "--username="+username)
+		req.password = password
+	}
+	if insecure {
+		req.args = append(req.args, "--insecure-skip-tls-verify=true")
+	}
+	return req
+}
+
+// tokenKubeconfig returns a kubeconfig holding only the token for the server,
+// in the form "oc login" compares servers in.
+func (req loginRequest) tokenKubeconfig() ([]byte, error) {
+	server := req.server
+	if !strings.Contains(server, "://") {
+		server = "https://" + server
+	}
+	const name = "oc-navigator-login"
+	cluster := map[string]interface{}{"server": server}
+	if req.insecure {
+		cluster["insecure-skip-tls-verify"] = true
+	}
+	return json.Marshal(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Config",
+		"clusters":   []interface{}{map[string]interface{}{"name": name, "cluster": cluster}},
+		"users":      []interface{}{map[string]interface{}{"name": name, "user": map[string]string{"token": req.token}}},
+		"contexts": []interface{}{map[string]interface{}{"name": name,
+			"context": map[string]string{"cluster": name, "user": name}}},
+	})
+}
+
+// kubeconfigFiles returns the kubeconfig files oc reads: those in KUBECONFIG,
+// or ~/.kube/config.
+func kubeconfigFiles() ([]string, error) {
+	var files []string
+	for _, file := range filepath.SplitList(os.Getenv("KUBECONFIG")) {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	if len(files) > 0 {
+		return files, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	return []string{filepath.Join(home, ".kube", "config")}, nil
+}
+
+// run runs the login. With a token, the temporary kubeconfig is appended to
+// the user's files so that oc still saves the new session in the first of
+// them; it is removed again afterwards.
+func (req loginRequest) run(tool cliTool) (string, error) {
+	if req.token == "" {
+		return runOCWith(tool, func(cmd *exec.Cmd) {
+			cmd.Stdin = strings.NewReader(req.password + "\n")
+		}, req.args...)
+	}
+	if req.server == "" {
+		return "", fmt.Errorf("enter the server to log in to with a token")
+	}
+
+	files, err := kubeconfigFiles()
+	if err != nil {
+		return "", err
+	}
+	// oc writes new entries to the first existing file of the list
+	if _, err := os.Stat(files[0]); errors.Is(err, os.ErrNotExist) {
+		if err := os.MkdirAll(filepath.Dir(files[0]), 0o700); err != nil {
+			return "", err
+		}
+		if err := os.WriteFile(files[0], nil, 0o600); err != nil {
+			return "", err
+		}
+	}
+
+	data, err := req.tokenKubeconfig()
+	if err != nil {
+		return "", err
+	}
+	tmp, err := os.CreateTemp("", "oc-navigator-login-*.kubeconfig")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+
+	kubeconfig := strings.Join(append(files, tmp.Name()), string(os.PathListSeparator))
+	return runOCWith(tool, func(cmd *exec.Cmd) {
+		cmd.Env = append(os.Environ(), "KUBECONFIG="+kubeconfig)
+	}, req.args...)
+}
+
+// showLoginDialog asks for a server and credentials and runs "oc login".
+// When retry is set that command runs again after a successful login.
+func (nav *OCNavigator) showLoginDialog(retry string) {
+	if nav.cli.kubectl {
+		nav.notify(notifyWarn, "kubectl cannot log in: update the credentials in your kubeconfig")
+		return
+	}
+	server := ""
+	if nav.health != nil {
+		server = nav.health.current().server
+	}
+
+	form := tview.NewForm().
+		AddInputField("Server: ", server, 50, nil, nil).
+		AddPasswordField("Token: ", "", 50, '*', nil).
+		AddInputField("Username: ", "", 30, nil, nil).
+		AddPasswordField("Password: ", "", 30, '*', nil).
+		AddCheckbox("Skip TLS verification: ", false, nil)
+
+	field := func(label string) string {
+		return strings.TrimSpace(form.GetFormItemByLabel(label).(*tview.InputField).GetText())
+	}
+	form.AddButton("Log in", func() {
+		token := field("Token: ")
+		username := field("Username: ")
+		if token == "" && username == "" {
+			nav.notify(notifyWarn, "Enter a token or a username and password")
+			return
+		}
+		password := form.GetFormItemByLabel("Password: ").(*tview.InputField).GetText()
+		insecure := form.GetFormItemByLabel("Skip TLS verification: ").(*tview.Checkbox).IsChecked()
+		nav.closeOverlay()
+		nav.login(newLoginRequest(field("Server: "), token, username, password, insecure), retry)
+	}).
+		AddButton("Cancel", func() {
+			nav.closeOverlay()
+		})
+
+	title := " Log in (token, or username and password) "
+	if retry != "" {
+		title = " Session expired: log in again "
+	}
+	form.SetBorder(true).SetTitle(title).SetTitleAlign(tview.AlignLeft)
+	form.SetCancelFunc(nav.closeOverlay)
+	if server == "" {
+		form.SetFocus(0)
+	} else {
+		form.SetFocus(1)
+	}
+	nav.showCenteredOverlay(form, 70, 15)
+}
+
+// login runs "oc login" in the background. It is never echoed or added to the
+// command history.
+func (nav *OCNavigator) login(req loginRequest, retry string) {
+	nav.notify(notifyInfo, "Logging in…")
+	go func() {
+		output, err := req.run(nav.cli)
+		nav.app.QueueUpdateDraw(func() {
+			if err != nil {
+				nav.notify(notifyError, "Login failed: "+firstLine(output, err))
+				return
+			}
+			nav.notify(notifySuccess, "Logged in")
+			nav.sessionChanged()
+			if retry != "" {
+				nav.executeCommand(retry)
+			}
+		})
+	}()
+}
+
+// showLogoutDialog confirms and runs "oc logout".
+func (nav *OCNavigator) showLogoutDialog() {
+	modal := tview.NewModal().
+		SetText("Log out of the current session?\nThe token will be revoked on the server.").
+		AddButtons([]string{"Log out", "Cancel"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			nav.closeOverlay()
+			if buttonLabel != "Log out" {
+				return
+			}
+			go func() {
+				output, err := runOC(nav.cli, "logout")
+				nav.app.QueueUpdateDraw(func() {
+					if err != nil {
+						nav.notify(notifyError, "Logout failed: "+firstLine(output, err))
+						return
+					}
+					nav.notify(notifySuccess, "Logged out")
+					nav.sessionChanged()
+				})
+			}()
+		})
+
+	nav.showOverlay(modal)
+}
+
+// sessionChanged reloads everything that depends on who is logged in where.
+func (nav *OCNavigator) sessionChanged() {
+	nav.getCurrentContext()
+	nav.getCurrentProject()
+	nav.updateStatusBar()
+	nav.rebuildRootMenu()
+	nav.checkHealthNow()
+	nav.refreshPermissions()
+	nav.refreshCapabilities()
+}
+
+// runOC runs oc through tool with a timeout and returns its combined output.
+func runOC(tool cliTool, args ...string) (string, error) {
+	return runOCWith(tool, nil, args...)
+}
+
+// runOCWith is runOC with a function that sets up the input or environment
+// of the command before it starts.
+func runOCWith(tool cliTool, prepare func(cmd *exec.Cmd), args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), loginTimeout)
+	defer cancel()
+	cmd, err := tool.command(ctx, args...)
+	if err != nil {
+		return "", err
+	}
+	if prepare != nil {
+		prepare(cmd)
+	}
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timed out after %s", loginTimeout)
+	}
+	return string(output), err
+}
+
+// firstLine returns the first line of output, or err when there is none.
+func firstLine(output string, err error) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(output), "\n")
+	if line == "" {
+		return err.Error()
+	}
+	return line
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewLoginRequest(t *testing.T) {
+	tests := []struct {
+		name                              string
+		server, token, username, password string
+		insecure                          bool
+		args                              []string
+	}{
+		{name: "token", server: "https://api.example:6443", token: "sha256~secret",
+			args: []string{"login", "https://api.example:6443"}},
+		{name: "password", server: "https://api.example:6443", username: "alice", password: "s3cret",
+			args: []string{"login", "https://api.example:6443", "--username=alice"}},
+		{name: "token wins", token: "sha256~secret", username: "alice", password: "s3cret", insecure: true,
+			args: []string{"login", "--insecure-skip-tls-verify=true"}},
+	}
+	for _, tt := range tests {
+		req := newLoginRequest(tt.server, tt.token, tt.username, tt.password, tt.insecure)
+		if !reflect.DeepEqual(req.args, tt.args) {
+			t.Errorf("%s: args = %q, want %q", tt.name, req.args, tt.args)
+		}
+		for _, arg := range req.args {
+			if strings.Contains(arg, "secret") {
+				t.Errorf("%s: secret on the command line: %q", tt.name, req.args)
+			}
+		}
+	}
+}
+
+func TestTokenKubeconfig(t *testing.T) {
+	tests := []struct {
+		server   string
+		insecure bool
+		want     string
+	}{
+		{server: "api.example:6443", want: "https://api.example:6443"},
+		{server: "https://api.example:6443", insecure: true, want: "https://api.example:6443"},
+	}
+	for _, tt := range tests {
+		data, err := newLoginRequest(tt.server, "sha256~secret", "", "", tt.insecure).tokenKubeconfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var config struct {
+			Clusters []struct {
+				Cluster struct {
+					Server   string `json:"server"`
+					Insecure bool   `json:"insecure-skip-tls-verify"`
+				} `json:"cluster"`
+			} `json:"clusters"`
+			Users []struct {
+				User struct {
+					Token string `json:"token"`
+				} `json:"user"`
+			} `json:"users"`
+		}
+		if err := json.Unmarshal(data, &config); err != nil {
+			t.Fatal(err)
+		}
+		if len(config.Clusters) != 1 || config.Clusters[0].Cluster.Server != tt.want || config.Clusters[0].Cluster.Insecure != tt.insecure {
+			t.Errorf("%s: clusters = %+v", tt.server, config.Clusters)
+		}
+		if len(config.Users) != 1 || config.Users[0].User.Token != "sha256~secret" {
+			t.Errorf("%s: users = %+v", tt.server, config.Users)
+		}
+	}
+}
+
+func TestKubeconfigFiles(t *testing.T) {
+	t.Setenv("KUBECONFIG", "/a/config::/b/config")
+	files, err := kubeconfigFiles()
+	if err != nil || !reflect.DeepEqual(files, []string{"/a/config", "/b/config"}) {
+		t.Errorf("kubeconfigFiles() = %q, %v", files, err)
+	}
+
+	t.Setenv("KUBECONFIG", "")
+	t.Setenv("HOME", "/home/alice")
+	files, err = kubeconfigFiles()
+	if err != nil || !reflect.DeepEqual(files, []string{"/home/alice/.kube/config"}) {
+		t.Errorf("kubeconfigFiles() = %q, %v", files, err)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// logStream is a running "oc logs -f" whose output is appended to the
+// command view as it arrives.
+type logStream struct {
+	pod    string
+	cancel context.CancelFunc
+}
+
+// followLogs streams the logs of pod into the command view without blocking
+// the UI. The namespace may be empty for the current project. The stream
+// stops when the back key is pressed or another command runs.
+func (nav *OCNavigator) followLogs(pod, namespace string) {
+	nav.stopWatch()
+	nav.stopLiveWatch()
+	nav.stopLogStream()
+	nav.closeDocument()
+
+	args := []string{"logs", "-f", pod}
+	if namespace != "" {
+		args = append(args, "-n", namespace)
+	}
+	command := "oc " + strings.Join(args, " ")
+	nav.commandHistory = append(nav.commandHistory, command)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ls := &logStream{pod: pod, cancel: cancel}
+	nav.logStream = ls
+
+	nav.commandView.Clear()
+	fmt.Fprintf(nav.commandView, "%s\n\n", nav.theme.paint(roleCommand, "$ "+nav.cli.display(command)))
+	nav.commandView.SetTitle(fmt.Sprintf(" Logs: %s (following, %s to stop) ", pod, nav.keymap.label(actionBack)))
+	nav.commandView.ScrollToEnd()
+	nav.app.SetFocus(nav.commandView)
+
+	go func() {
+		cmd, err := nav.cli.command(ctx, args...)
+		var stdout io.ReadCloser
+		if err == nil {
+			stdout, err = cmd.StdoutPipe()
+		}
+		if err == nil {
+			cmd.Stderr = cmd.Stdout
+			err = cmd.Start()
+		}
+		if err == nil {
+			scanner := bufio.NewScanner(stdout)
+			for scanner.Scan() {
+				line := scanner.Text()
+				nav.app.QueueUpdateDraw(func() {
+					if nav.logStream == ls {
+						fmt.Fprintln(nav.commandView, tview.Escape(line))
+					}
+				})
+			}
+			err = cmd.Wait()
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+		nav.app.QueueUpdateDraw(func() {
+			if nav.logStream != ls {
+				return
+			}
+			if err != nil {
+				fmt.Fprintf(nav.commandView, "\n%s\n", nav.theme.paint(roleError, fmt.Sprintf("Error: %v", err)))
+			}
+			fmt.Fprintf(nav.commandView, "%s\n", nav.theme.paint(roleMuted, "-- log stream ended --"))
+			nav.commandView.SetTitle(fmt.Sprintf(" Logs: %s (ended) ", pod))
+		})
+	}()
+}
+
+// stopLogStream stops following logs, if a stream is running.
+func (nav *OCNavigator) stopLogStream() {
+	if nav.logStream == nil {
+		return
+	}
+	nav.logStream.cancel()
+	nav.logStream = nil
+	nav.commandView.SetTitle(" Command Output ")
+	nav.app.SetFocus(nav.menuList)
+}
@@ -1,6 +1,9 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
@@ -19,59 +22,134 @@ type MenuItem struct {
 	Description string      `json:"description,omitempty"`
 	Submenu     []*MenuItem `json:"submenu,omitempty"`
 	IsExec      bool        `json:"is_executable"`
+	Shortcut    string      `json:"shortcut,omitempty"`
+	Resource    string      `json:"resource,omitempty"`
 }
 
 type OCNavigator struct {
-	app             *tview.Application
-	mainFlex        *tview.Flex
-	mainLayout      *tview.Flex
-	menuList        *tview.List
-	menuFooter      *tview.TextView
-	detailView      *tview.TextView
-	commandView     *tview.TextView
-	statusBar       *tview.TextView
-	currentMenu     []*MenuItem
-	menuStack       [][]*MenuItem
-	titleStack      []string
-	currentContext  string
-	currentProject  string
-	commandHistory  []string
-	outputBuffer    strings.Builder
-}
-
-func NewOCNavigator() *OCNavigator {
+	app            *tview.Application
+	mainFlex       *tview.Flex
+	leftPanel      *tview.Flex
+	rightPanel     *tview.Flex
+	mainLayout     *tview.Flex
+	pages          *tview.Pages
+	menuList       *tview.List
+	menuFooter     *tview.TextView
+	detailView     *tview.TextView
+	commandView    *tview.TextView
+	resourceTable  *tview.Table
+	documentTree   *tview.TreeView
+	outputPages    *tview.Pages
+	statusBar      *tview.TextView
+	bottomBar      *tview.Pages
+	breadcrumbBar  *tview.TextView
+	baseMenu       []*MenuItem
+	rootMenu       []*MenuItem
+	currentMenu    []*MenuItem
+	menuStack      [][]*MenuItem
+	menuParents    []*MenuItem // the items whose submenus are open, parallel to menuStack
+	titleStack     []string
+	currentContext string
+	currentProject string
+	commandHistory []string
+	outputBuffer   strings.Builder
+
+	lastListCommand string
+	filter          sessionFilter
+	watchInterval   time.Duration
+	watch           *watchState
+	live            *liveWatch
+	palette         *commandPalette
+	logStream       *logStream
+	document        *documentView
+	lastJSON        *lastJSON
+	search          *searchState
+	explainCache    map[string]string
+	helpView        *tview.TextView
+	activeItem      *MenuItem
+	overlay         tview.Primitive
+	overlayPage     tview.Primitive // overlay with its centering, for reopening it
+	overlayFocus    tview.Primitive
+	zoomed          tview.Primitive
+	stacked         bool
+	screenWidth     int
+	screen          tcell.Screen
+
+	cli             cliTool // the tool selected with -cli
+	config          *Config
+	configErr       error
+	keymap          *Keymap
+	notifications   *notifier
+	health          *healthChecker
+	perms           *permissions
+	permsSeq        int
+	menuOrigins     map[*MenuItem]*MenuItem
+	apis            *apiCatalog
+	apisSeq         int
+	theme           Theme
+	favoritesMenu   *MenuItem
+	favoriteIndices []int
+}
+
+func NewOCNavigator(tool cliTool) *OCNavigator {
 	nav := &OCNavigator{
 		app:            tview.NewApplication(),
+		cli:            tool,
 		menuStack:      make([][]*MenuItem, 0),
 		titleStack:     make([]string, 0),
 		commandHistory: make([]string, 0),
+		watchInterval:  defaultWatchInterval,
+		explainCache:   make(map[string]string),
 	}
 
 	nav.getCurrentContext()
 	nav.getCurrentProject()
+	nav.config, nav.configErr = loadConfig()
+	keyProblems := nav.loadKeymap()
+	var notifyProblems []string
+	nav.notifications, notifyProblems = newNotifier(nav.config.NotificationTimeouts)
+	theme, themeErr := loadTheme(nav.config.Theme)
+	nav.theme = theme
+	nav.theme.install()
 	nav.initializeUI()
 	nav.buildMainMenu()
+	if nav.configErr != nil {
+		nav.notify(notifyError, fmt.Sprintf("Error loading settings: %v", nav.configErr))
+	}
+	if themeErr != nil {
+		nav.notify(notifyError, fmt.Sprintf("Error loading theme: %v", themeErr))
+	}
+	if len(notifyProblems) > 0 {
+		nav.notify(notifyError, "Error in notification_timeouts: "+strings.Join(notifyProblems, ", "))
+	}
+
+	nav.startHealthCheck()
+	nav.refreshPermissions()
+	nav.refreshCapabilities()
+
+	keyProblems = append(keyProblems, nav.keymap.checkMenuShortcuts(nav.baseMenu, "")...)
+	if len(keyProblems) > 0 {
+		nav.showKeymapProblems(keyProblems)
+	}
 
 	return nav
 }
 
 func (nav *OCNavigator) getCurrentContext() {
-	cmd := exec.Command("oc", "config", "current-context")
-	output, err := cmd.Output()
+	output, err := ocOutput(context.Background(), nav.cli, "config", "current-context")
 	if err != nil {
 		nav.currentContext = "Unknown"
 	} else {
-		nav.currentContext = strings.TrimSpace(string(output))
+		nav.currentContext = output
 	}
 }
 
 func (nav *OCNavigator) getCurrentProject() {
-	cmd := exec.Command("oc", "project", "-q")
-	output, err := cmd.Output()
-	if err != nil {
+	output, err := ocOutput(context.Background(), nav.cli, "project", "-q")
+	if err != nil || output == "" {
 		nav.currentProject = "default"
 	} else {
-		nav.currentProject = strings.TrimSpace(string(output))
+		nav.currentProject = output
 	}
 }
 
@@ -81,54 +159,96 @@ func (nav *OCNavigator) initializeUI() {
 	nav.menuFooter = tview.NewTextView().SetText("- Kini").SetTextAlign(tview.AlignCenter)
 	nav.detailView = tview.NewTextView().SetDynamicColors(true).SetScrollable(true)
 	nav.commandView = tview.NewTextView().SetDynamicColors(true).SetScrollable(true)
+	nav.resourceTable = tview.NewTable().SetSelectable(true, false).SetFixed(1, 0)
+	nav.documentTree = tview.NewTreeView()
 	nav.statusBar = tview.NewTextView().SetDynamicColors(true)
+	nav.breadcrumbBar = tview.NewTextView().SetDynamicColors(true)
+	nav.theme.styleList(nav.menuList)
+	nav.theme.styleTable(nav.resourceTable)
 
 	// Style components
 	nav.menuList.SetBorder(true).SetTitle(" Navigation ").SetTitleAlign(tview.AlignLeft)
 	nav.menuFooter.SetBorder(true).SetBorderPadding(0, 0, 1, 1)
 	nav.detailView.SetBorder(true).SetTitle(" Details ").SetTitleAlign(tview.AlignLeft)
 	nav.commandView.SetBorder(true).SetTitle(" Command Output ").SetTitleAlign(tview.AlignLeft)
+	nav.resourceTable.SetBorder(true).SetTitleAlign(tview.AlignLeft)
+	nav.documentTree.SetBorder(true).SetTitleAlign(tview.AlignLeft)
+
+	// The output pane shows plain command output, the live resource table or
+	// a YAML or JSON resource
+	nav.outputPages = tview.NewPages().
+		AddPage("text", nav.commandView, true, true).
+		AddPage("table", nav.resourceTable, true, false).
+		AddPage("document", nav.documentTree, true, false)
 
 	// Create left panel with menu and footer
-	leftPanel := tview.NewFlex().SetDirection(tview.FlexRow).
+	nav.leftPanel = tview.NewFlex().SetDirection(tview.FlexRow).
 		AddItem(nav.menuList, 0, 1, true).
 		AddItem(nav.menuFooter, 3, 0, false)
 
-	// Create right panel with details and command output
-	rightPanel := tview.NewFlex().SetDirection(tview.FlexRow).
-		AddItem(nav.detailView, 0, 1, false).
-		AddItem(nav.commandView, 0, 1, false)
+	// Right panel with details and command output, arranged by applyLayout
+	nav.rightPanel = tview.NewFlex().SetDirection(tview.FlexRow)
+	nav.mainFlex = tview.NewFlex()
+	nav.applyLayout()
 
-	nav.mainFlex = tview.NewFlex().
-		AddItem(leftPanel, 0, 1, true).
-		AddItem(rightPanel, 0, 2, false)
+	// The bottom row shows the status bar, or an input while prompting
+	nav.bottomBar = tview.NewPages().AddPage("status", nav.statusBar, true, true)
 
 	nav.mainLayout = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(nav.breadcrumbBar, 1, 0, false).
 		AddItem(nav.mainFlex, 0, 1, true).
-		AddItem(nav.statusBar, 1, 0, false)
+		AddItem(nav.bottomBar, 1, 0, false)
 
 	// Set up event handlers
 	nav.menuList.SetSelectedFunc(nav.onMenuSelect)
 	nav.menuList.SetChangedFunc(nav.onMenuChange)
+	nav.resourceTable.SetSelectionChangedFunc(func(row, column int) {
+		nav.updateBreadcrumb()
+	})
 
 	// Global key bindings
 	nav.app.SetInputCapture(nav.handleGlobalKeys)
 
-	nav.app.SetRoot(nav.mainLayout, true)
+	nav.menuList.SetMouseCapture(nav.handleMenuMouse)
+	nav.resourceTable.SetSelectedFunc(func(row, column int) {
+		nav.showRowActions(row)
+	})
+	nav.resourceTable.SetMouseCapture(nav.handleTableMouse)
+	nav.resourceTable.SetInputCapture(nav.handleTableKeys)
+	nav.documentTree.SetSelectedFunc(nav.toggleDocumentFold)
+	nav.documentTree.SetChangedFunc(func(node *tview.TreeNode) {
+		nav.updateDocumentTitle()
+	})
+	nav.documentTree.SetInputCapture(nav.handleDocumentKeys)
+
+	// Highlight the border of whichever pane has focus
+	for _, box := range []*tview.Box{nav.menuList.Box, nav.detailView.Box, nav.commandView.Box, nav.resourceTable.Box, nav.documentTree.Box} {
+		box := box
+		box.SetFocusFunc(func() { box.SetBorderColor(nav.theme.color(roleFocus)) })
+		box.SetBlurFunc(func() { box.SetBorderColor(tview.Styles.BorderColor) })
+	}
+
+	nav.app.EnableMouse(true)
+	nav.app.SetMouseCapture(nav.blockMouseBehindOverlay)
+	nav.app.SetBeforeDrawFunc(nav.updateStacking)
+
+	// Overlays are shown on top of the main layout
+	nav.pages = tview.NewPages().AddPage("main", nav.mainLayout, true, true)
+	nav.app.SetRoot(nav.pages, true)
 	nav.updateStatusBar()
 }
 
 func (nav *OCNavigator) buildMainMenu() {
-	nav.currentMenu = []*MenuItem{
+	nav.baseMenu = []*MenuItem{
 		{
 			Name:        "Projects & Namespaces",
 			Description: "Manage OpenShift projects and namespaces",
 			Submenu: []*MenuItem{
 				{Name: "List all projects", Command: "oc get projects", Description: "Show all available projects", IsExec: true},
-				{Name: "Current project info", Command: "oc project", Description: "Display current project information", IsExec: true},
-				{Name: "Switch project", Command: "", Description: "Interactive project switching", IsExec: false},
-				{Name: "Create new project", Command: "", Description: "Create a new OpenShift project", IsExec: false},
-				{Name: "Delete project", Command: "", Description: "Delete an existing project", IsExec: false},
+				{Name: "Current project info", Command: "oc project", Description: "Display current project information", IsExec: true, Resource: "projects"},
+				{Name: "Switch project", Command: "", Description: "Interactive project switching", IsExec: false, Resource: "projects"},
+				{Name: "Create new project", Command: "", Description: "Create a new OpenShift project", IsExec: false, Resource: "projectrequests"},
+				{Name: "Delete project", Command: "", Description: "Delete an existing project", IsExec: false, Resource: "projects"},
 			},
 		},
 		{
@@ -183,9 +303,9 @@ func (nav *OCNavigator) buildMainMenu() {
 			Submenu: []*MenuItem{
 				{Name: "Events", Command: "oc get events --sort-by=.metadata.creationTimestamp", Description: "Show recent events", IsExec: true},
 				{Name: "Node status", Command: "oc get nodes", Description: "Check node status", IsExec: true},
-				{Name: "Resource usage", Command: "oc top nodes", Description: "Show resource usage by nodes", IsExec: true},
-				{Name: "Pod logs", Command: "", Description: "View pod logs", IsExec: false},
-				{Name: "Follow logs", Command: "", Description: "Follow pod logs in real-time", IsExec: false},
+				{Name: "Resource usage", Command: "oc top nodes", Description: "Show resource usage by nodes", IsExec: true, Resource: "nodes.metrics.k8s.io"},
+				{Name: "Pod logs", Command: "", Description: "View pod logs", IsExec: false, Resource: "pods"},
+				{Name: "Follow logs", Command: "", Description: "Follow pod logs in real-time", IsExec: false, Resource: "pods"},
 			},
 		},
 		{
@@ -210,6 +330,15 @@ func (nav *OCNavigator) buildMainMenu() {
 				{Name: "Namespaces", Command: "oc get namespaces", Description: "List all namespaces", IsExec: true},
 			},
 		},
+		{
+			Name:        "Session",
+			Description: "Log in to a cluster or end the current session",
+			Submenu: []*MenuItem{
+				{Name: "Log in", Command: "oc login", Description: "Log in with a token, or a username and password", IsExec: false},
+				{Name: "Log out", Command: "oc logout", Description: "Log out and revoke the current token", IsExec: false},
+				{Name: "Who am I", Command: "oc whoami", Description: "Show the logged-in user", IsExec: true},
+			},
+		},
 		{
 			Name:        "Custom Commands",
 			Description: "Execute custom oc commands",
@@ -222,16 +351,66 @@ func (nav *OCNavigator) buildMainMenu() {
 		},
 	}
 
-	nav.populateMenu()
+	menu, err := loadMenuFile()
+	if err != nil {
+		nav.notify(notifyError, fmt.Sprintf("Error loading menu file, using built-in menu: %v", err))
+	} else if menu != nil {
+		nav.baseMenu = menu
+	}
+
+	nav.rebuildRootMenu()
+	nav.updateBreadcrumb()
 }
 
 func (nav *OCNavigator) populateMenu() {
 	nav.menuList.Clear()
-	for _, item := range nav.currentMenu {
-		nav.menuList.AddItem(item.Name, item.Description, 0, nil)
+	inFavorites := nav.inFavoritesMenu()
+	shortcuts := menuShortcuts(nav.currentMenu)
+	for i, item := range nav.currentMenu {
+		name, description := item.Name, item.Description
+		if !inFavorites && nav.isFavorite(item) {
+			name = "★ " + name
+		}
+		if nav.unsupported(item) != "" {
+			name = nav.theme.paint(roleMuted, name+" (unavailable)")
+			description = nav.theme.paint(roleMuted, description)
+		} else if nav.missingAccess(item) != "" {
+			name = nav.theme.paint(roleMuted, name+" (no access)")
+			description = nav.theme.paint(roleMuted, description)
+		}
+		nav.menuList.AddItem(name, description, shortcuts[i], nil)
 	}
 }
 
+// menuShortcuts returns the shortcut rune for each item of menu. Items
+// without a configured shortcut get the next free digit from 1 to 9.
+func menuShortcuts(menu []*MenuItem) []rune {
+	shortcuts := make([]rune, len(menu))
+	used := make(map[rune]bool)
+	for i, item := range menu {
+		if item.Shortcut != "" {
+			shortcuts[i] = []rune(item.Shortcut)[0]
+			used[shortcuts[i]] = true
+		}
+	}
+
+	next := '1'
+	for i := range menu {
+		if shortcuts[i] != 0 {
+			continue
+		}
+		for next <= '9' && used[next] {
+			next++
+		}
+		if next > '9' {
+			break
+		}
+		shortcuts[i] = next
+		next++
+	}
+	return shortcuts
+}
+
 func (nav *OCNavigator) onMenuSelect(index int, mainText string, secondaryText string, shortcut rune) {
 	if index >= len(nav.currentMenu) {
 		return
@@ -242,14 +421,19 @@ func (nav *OCNavigator) onMenuSelect(index int, mainText string, secondaryText s
 	if selectedItem.Submenu != nil {
 		// Navigate to submenu
 		nav.menuStack = append(nav.menuStack, nav.currentMenu)
+		nav.menuParents = append(nav.menuParents, selectedItem)
 		nav.titleStack = append(nav.titleStack, nav.menuList.GetTitle())
 		nav.currentMenu = selectedItem.Submenu
+		nav.activeItem = nil
 		nav.populateMenu()
 		nav.menuList.SetTitle(fmt.Sprintf(" %s ", selectedItem.Name))
 		nav.menuList.SetCurrentItem(0)
+		nav.updateBreadcrumb()
 	} else if selectedItem.IsExec && selectedItem.Command != "" {
 		// Execute command
-		nav.executeCommand(selectedItem.Command)
+		nav.activeItem = selectedItem
+		nav.executeCommand(nav.filtered(selectedItem.Command))
+		nav.updateBreadcrumb()
 	} else {
 		// Handle special cases
 		switch selectedItem.Name {
@@ -267,6 +451,10 @@ func (nav *OCNavigator) onMenuSelect(index int, mainText string, secondaryText s
 			nav.showPodLogsDialog()
 		case "Follow logs":
 			nav.showFollowLogsDialog()
+		case "Log in":
+			nav.showLoginDialog("")
+		case "Log out":
+			nav.showLogoutDialog()
 		default:
 			nav.showItemDetails(selectedItem)
 		}
@@ -282,50 +470,128 @@ func (nav *OCNavigator) onMenuChange(index int, mainText string, secondaryText s
 
 func (nav *OCNavigator) showItemDetails(item *MenuItem) {
 	nav.detailView.Clear()
-	fmt.Fprintf(nav.detailView, "[yellow]%s[white]\n\n", item.Name)
+	fmt.Fprintf(nav.detailView, "%s\n\n", nav.theme.paint(roleTitle, item.Name))
 	fmt.Fprintf(nav.detailView, "%s\n\n", item.Description)
 
 	if item.Command != "" {
-		fmt.Fprintf(nav.detailView, "[cyan]Command:[white] %s\n\n", item.Command)
+		fmt.Fprintf(nav.detailView, "%s %s\n\n", nav.theme.paint(roleKey, "Command:"), tview.Escape(nav.cli.display(item.Command)))
 	}
 
 	if item.Submenu != nil {
-		fmt.Fprintf(nav.detailView, "[green]Submenu items:[white]\n")
+		fmt.Fprintf(nav.detailView, "%s\n", nav.theme.paint(roleSuccess, "Submenu items:"))
 		for _, subitem := range item.Submenu {
 			fmt.Fprintf(nav.detailView, "• %s\n", subitem.Name)
 		}
 	}
 
-	if item.IsExec {
-		fmt.Fprintf(nav.detailView, "\n[green]Press Enter to execute[white]")
+	if reason := nav.unsupported(item); reason != "" {
+		fmt.Fprintf(nav.detailView, "\n%s\n%s", nav.theme.paint(roleError, "Not available on this cluster:"), tview.Escape(reason))
+	} else if missing := nav.missingAccess(item); missing != "" {
+		fmt.Fprintf(nav.detailView, "\n%s\n%s", nav.theme.paint(roleError, "You do not have access to this:"), tview.Escape(missing))
+	} else if item.IsExec {
+		fmt.Fprintf(nav.detailView, "\n%s", nav.theme.paint(roleSuccess, "Press Enter to execute"))
+	}
+
+	if nav.inFavoritesMenu() {
+		fmt.Fprintf(nav.detailView, "\n\n%s: unstar | %s/%s: move up/down | %s: only on current context",
+			nav.keyLabel(actionFavorite), nav.keyLabel(actionFavoriteUp),
+			nav.keyLabel(actionFavoriteDown), nav.keyLabel(actionFavoriteScope))
+	} else if item != nav.favoritesMenu {
+		fmt.Fprintf(nav.detailView, "\n\n%s: add to favorites", nav.keyLabel(actionFavorite))
 	}
 }
 
 func (nav *OCNavigator) executeCommand(command string) {
+	nav.stopWatch()
+	nav.stopLiveWatch()
+	nav.stopLogStream()
+	nav.closeDocument()
+	nav.clearSearch()
 	nav.commandView.Clear()
-	nav.setStatus("Executing: " + command)
+	nav.notify(notifyInfo, "Executing: "+command)
 
 	// Add to history
 	nav.commandHistory = append(nav.commandHistory, command)
+	if isListCommand(command) {
+		nav.lastListCommand = command
+	}
 
 	// Show command being executed
-	fmt.Fprintf(nav.commandView, "[yellow]$ %s[white]\n\n", command)
+	fmt.Fprintf(nav.commandView, "%s\n\n", nav.theme.paint(roleCommand, "$ "+nav.cli.display(command)))
 
-	// Execute command
-	parts := strings.Fields(command)
-	if len(parts) == 0 {
-		return
+	// Execute command. YAML and JSON are fetched as JSON to show them as a tree
+	format := documentFormat(command)
+	runAs := command
+	if format != "" {
+		runAs = asJSON(command)
 	}
+	output, warnings, err := runCommand(nav.cli, runAs)
+	if err == nil && (format != "" || looksLikeJSON(output)) {
+		doc, parseErr := parseDocument([]byte(output))
+		switch {
+		case parseErr == nil:
+			nav.lastJSON = &lastJSON{command: command, root: doc}
+			if format != "" {
+				output = doc.text(format, false)
+				nav.showDocument(command, format, doc)
+			}
+		case runAs != command:
+			// Show what was asked for rather than the JSON fetched instead
+			output, warnings, err = runCommand(nav.cli, command)
+		}
+	}
+	fmt.Fprintf(nav.commandView, "%s", output)
+	// A failed command carries its stderr in the error; on success it only
+	// holds warnings, such as deprecation notices
+	if warnings = strings.TrimRight(warnings, "\n"); err == nil && warnings != "" {
+		fmt.Fprintf(nav.commandView, "\n%s\n", nav.theme.paint(roleWarning, tview.Escape(warnings)))
+	}
+	if err != nil {
+		if output != "" {
+			fmt.Fprintln(nav.commandView)
+		}
+		nav.writeCommandError(nav.commandView, err)
+	}
+
+	var cmdErr *commandError
+	if errors.As(err, &cmdErr) && cmdErr.category == errUnauthorized {
+		nav.notify(notifyError, "Not logged in or token expired")
+		nav.showLoginDialog(command)
+	} else if err != nil {
+		nav.notify(notifyError, "Command failed: "+err.Error())
+	} else {
+		nav.notify(notifySuccess, "Command completed")
+	}
+}
 
-	cmd := exec.Command(parts[0], parts[1:]...)
-	output, err := cmd.CombinedOutput()
+// runCommand splits command on whitespace, runs it and returns its stdout
+// and, separately, its stderr, where oc prints warnings even on success.
+// When it fails the error is a classified *commandError carrying stderr.
+func runCommand(tool cliTool, command string) (string, string, error) {
+	return runCommandContext(context.Background(), tool, command)
+}
 
-	if err != nil {
-		fmt.Fprintf(nav.commandView, "[red]Error: %v[white]\n\n", err)
+// runCommandContext is runCommand with a context that can stop the command.
+func runCommandContext(ctx context.Context, tool cliTool, command string) (string, string, error) {
+	parts := strings.Fields(command)
+	if len(parts) == 0 {
+		return "", "", nil
 	}
 
-	fmt.Fprintf(nav.commandView, "%s", string(output))
-	nav.setStatus("Command completed")
+	cmd := exec.CommandContext(ctx, parts[0], parts[1:]...)
+	if parts[0] == "oc" {
+		var err error
+		if cmd, err = tool.command(ctx, parts[1:]...); err != nil {
+			return "", "", err
+		}
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return stdout.String(), stderr.String(), classifyError(err, stderr.String())
+	}
+	return stdout.String(), stderr.String(), nil
 }
 
 func (nav *OCNavigator) showCustomCommandDialog() {
@@ -338,41 +604,77 @@ func (nav *OCNavigator) showCustomCommandDialog() {
 		AddFormItem(inputField).
 		AddButton("Execute", func() {
 			command := inputField.GetText()
+			// Close first so that a view opened by the command keeps focus
+			nav.closeOverlay()
 			if command != "" {
 				if !strings.HasPrefix(command, "oc ") {
 					command = "oc " + command
 				}
 				nav.executeCommand(command)
 			}
-			nav.app.SetRoot(nav.mainLayout, true)
+		}).
+		AddButton("Add to Favorites", func() {
+			command := inputField.GetText()
+			if command != "" {
+				if !strings.HasPrefix(command, "oc ") {
+					command = "oc " + command
+				}
+				nav.toggleCommandFavorite(command)
+			}
+			nav.closeOverlay()
 		}).
 		AddButton("Cancel", func() {
-			nav.app.SetRoot(nav.mainLayout, true)
+			nav.closeOverlay()
 		})
 
 	form.SetTitle(" Custom Command ").SetBorder(true)
-	nav.app.SetRoot(form, true)
+	form.SetCancelFunc(nav.closeOverlay)
+	nav.showOverlay(form)
 }
 
-func (nav *OCNavigator) showCommandHistory() {
-	historyText := "Command History"
+// showOverlay shows p on top of the main layout until closeOverlay is
+// called. Global keys are left to the overlay while it is open.
+func (nav *OCNavigator) showOverlay(p tview.Primitive) {
+	nav.showOverlayPage(p, p)
+}
 
-	for i, cmd := range nav.commandHistory {
-		historyText += fmt.Sprintf("\n%d. %s", i+1, cmd)
-	}
+// showCenteredOverlay shows p with the given size in the middle of the
+// screen, leaving the main layout visible around it.
+func (nav *OCNavigator) showCenteredOverlay(p tview.Primitive, width, height int) {
+	nav.showOverlayPage(p, centered(p, width, height))
+}
 
-	if len(nav.commandHistory) == 0 {
-		historyText += "\n\nNo commands in history"
+func (nav *OCNavigator) showOverlayPage(p, page tview.Primitive) {
+	if nav.overlay == nil {
+		nav.overlayFocus = nav.app.GetFocus()
 	}
+	nav.overlay = p
+	nav.overlayPage = page
+	nav.theme.styleOverlay(p)
+	nav.pages.AddPage("overlay", page, true, true)
+	nav.app.SetFocus(p)
+}
 
-	modal := tview.NewModal().
-		SetText(historyText).
-		AddButtons([]string{"Close"}).
-		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
-			nav.app.SetRoot(nav.mainLayout, true)
-		})
+// blockMouseBehindOverlay drops mouse events outside an open overlay so that
+// clicks cannot reach the panes underneath it.
+func (nav *OCNavigator) blockMouseBehindOverlay(event *tcell.EventMouse, action tview.MouseAction) (*tcell.EventMouse, tview.MouseAction) {
+	if box, ok := nav.overlay.(interface{ InRect(x, y int) bool }); ok && !box.InRect(event.Position()) {
+		return nil, action
+	}
+	return event, action
+}
 
-	nav.app.SetRoot(modal, true)
+// closeOverlay returns to the main layout, focusing the pane that had focus
+// before the overlay was opened.
+func (nav *OCNavigator) closeOverlay() {
+	nav.overlay = nil
+	nav.overlayPage = nil
+	nav.pages.RemovePage("overlay")
+	if nav.overlayFocus != nil {
+		nav.app.SetFocus(nav.overlayFocus)
+	} else {
+		nav.app.SetFocus(nav.menuList)
+	}
 }
 
 // showProjectSwitchDialog shows an input dialog for switching projects
@@ -386,19 +688,23 @@ func (nav *OCNavigator) showProjectSwitchDialog() {
 		AddFormItem(inputField).
 		AddButton("Switch", func() {
 			projectName := inputField.GetText()
+			// Close first so that a login form opened by the command stays up
+			nav.closeOverlay()
 			if projectName != "" {
 				nav.executeCommand(fmt.Sprintf("oc project %s", projectName))
 				nav.getCurrentProject()
 				nav.updateStatusBar()
+				nav.refreshPermissions()
+				nav.refreshCapabilities()
 			}
-			nav.app.SetRoot(nav.mainLayout, true)
 		}).
 		AddButton("Cancel", func() {
-			nav.app.SetRoot(nav.mainLayout, true)
+			nav.closeOverlay()
 		})
 
 	form.SetBorder(true).SetTitle(" Switch Project ").SetTitleAlign(tview.AlignLeft)
-	nav.app.SetRoot(form, true)
+	form.SetCancelFunc(nav.closeOverlay)
+	nav.showOverlay(form)
 }
 
 // showCreateProjectDialog shows an input dialog for creating a new project
@@ -419,6 +725,8 @@ func (nav *OCNavigator) showCreateProjectDialog() {
 		AddButton("Create", func() {
 			projectName := nameField.GetText()
 			description := descField.GetText()
+			// Close first so that a login form opened by the command stays up
+			nav.closeOverlay()
 			if projectName != "" {
 				cmd := fmt.Sprintf("oc new-project %s", projectName)
 				if description != "" {
@@ -427,15 +735,17 @@ func (nav *OCNavigator) showCreateProjectDialog() {
 				nav.executeCommand(cmd)
 				nav.getCurrentProject()
 				nav.updateStatusBar()
+				nav.refreshPermissions()
+				nav.refreshCapabilities()
 			}
-			nav.app.SetRoot(nav.mainLayout, true)
 		}).
 		AddButton("Cancel", func() {
-			nav.app.SetRoot(nav.mainLayout, true)
+			nav.closeOverlay()
 		})
 
 	form.SetBorder(true).SetTitle(" Create New Project ").SetTitleAlign(tview.AlignLeft)
-	nav.app.SetRoot(form, true)
+	form.SetCancelFunc(nav.closeOverlay)
+	nav.showOverlay(form)
 }
 
 // showDeleteProjectDialog shows an input dialog for deleting a project
@@ -453,15 +763,16 @@ func (nav *OCNavigator) showDeleteProjectDialog() {
 				// Show confirmation dialog
 				nav.showDeleteConfirmationDialog(projectName)
 			} else {
-				nav.app.SetRoot(nav.mainLayout, true)
+				nav.closeOverlay()
 			}
 		}).
 		AddButton("Cancel", func() {
-			nav.app.SetRoot(nav.mainLayout, true)
+			nav.closeOverlay()
 		})
 
 	form.SetBorder(true).SetTitle(" Delete Project ").SetTitleAlign(tview.AlignLeft)
-	nav.app.SetRoot(form, true)
+	form.SetCancelFunc(nav.closeOverlay)
+	nav.showOverlay(form)
 }
 
 // showDeleteConfirmationDialog shows a confirmation dialog before deleting a project
@@ -470,15 +781,18 @@ func (nav *OCNavigator) showDeleteConfirmationDialog(projectName string) {
 		SetText(fmt.Sprintf("Are you sure you want to delete project '%s'?\nThis action cannot be undone!", projectName)).
 		AddButtons([]string{"Delete", "Cancel"}).
 		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			// Close first so that a login form opened by the command stays up
+			nav.closeOverlay()
 			if buttonLabel == "Delete" {
 				nav.executeCommand(fmt.Sprintf("oc delete project %s", projectName))
 				nav.getCurrentProject()
 				nav.updateStatusBar()
+				nav.refreshPermissions()
+				nav.refreshCapabilities()
 			}
-			nav.app.SetRoot(nav.mainLayout, true)
 		})
 
-	nav.app.SetRoot(modal, true)
+	nav.showOverlay(modal)
 }
 
 // showPodLogsDialog shows an input dialog for viewing pod logs
@@ -492,17 +806,19 @@ func (nav *OCNavigator) showPodLogsDialog() {
 		AddFormItem(inputField).
 		AddButton("View Logs", func() {
 			podName := inputField.GetText()
+			// Close first so that a login form opened by the command stays up
+			nav.closeOverlay()
 			if podName != "" {
 				nav.executeCommand(fmt.Sprintf("oc logs %s", podName))
 			}
-			nav.app.SetRoot(nav.mainLayout, true)
 		}).
 		AddButton("Cancel", func() {
-			nav.app.SetRoot(nav.mainLayout, true)
+			nav.closeOverlay()
 		})
 
 	form.SetBorder(true).SetTitle(" View Pod Logs ").SetTitleAlign(tview.AlignLeft)
-	nav.app.SetRoot(form, true)
+	form.SetCancelFunc(nav.closeOverlay)
+	nav.showOverlay(form)
 }
 
 // showFollowLogsDialog shows an input dialog for following pod logs
@@ -516,105 +832,220 @@ func (nav *OCNavigator) showFollowLogsDialog() {
 		AddFormItem(inputField).
 		AddButton("Follow Logs", func() {
 			podName := inputField.GetText()
+			nav.closeOverlay()
 			if podName != "" {
-				nav.executeCommand(fmt.Sprintf("oc logs -f %s", podName))
+				nav.followLogs(podName, "")
 			}
-			nav.app.SetRoot(nav.mainLayout, true)
 		}).
 		AddButton("Cancel", func() {
-			nav.app.SetRoot(nav.mainLayout, true)
+			nav.closeOverlay()
 		})
 
 	form.SetBorder(true).SetTitle(" Follow Pod Logs ").SetTitleAlign(tview.AlignLeft)
-	nav.app.SetRoot(form, true)
+	form.SetCancelFunc(nav.closeOverlay)
+	nav.showOverlay(form)
 }
 
 func (nav *OCNavigator) handleGlobalKeys(event *tcell.EventKey) *tcell.EventKey {
-	switch event.Key() {
-	case tcell.KeyEscape:
-		if len(nav.menuStack) > 0 {
-			// Go back to previous menu
-			nav.currentMenu = nav.menuStack[len(nav.menuStack)-1]
-			nav.menuStack = nav.menuStack[:len(nav.menuStack)-1]
-
-			if len(nav.titleStack) > 0 {
-				nav.menuList.SetTitle(nav.titleStack[len(nav.titleStack)-1])
-				nav.titleStack = nav.titleStack[:len(nav.titleStack)-1]
-			} else {
-				nav.menuList.SetTitle(" Navigation ")
-			}
+	_, typing := nav.app.GetFocus().(*tview.InputField)
+	if nav.keymap.is(event, actionHelp) && !(typing && event.Key() == tcell.KeyRune) {
+		// Help is available everywhere, also on top of forms and dialogs
+		if !nav.helpShown() {
+			nav.showHelp()
+			return nil
+		}
+	}
+	if typing {
+		// Never hijack keys while the user is typing
+		return event
+	}
+	if nav.overlay != nil {
+		if nav.config.VimKeys && !nav.helpShown() && event.Key() == tcell.KeyRune && event.Rune() == 'q' {
+			nav.closeOverlay()
+			return nil
+		}
+		// Overlays such as the palette handle their own keys
+		return event
+	}
+	if nav.config.VimKeys {
+		if event = nav.handleVimKey(event); event == nil {
+			return nil
+		}
+	}
 
-			nav.populateMenu()
+	if event.Key() == tcell.KeyRune && event.Modifiers()&tcell.ModAlt != 0 && event.Rune() >= '0' && event.Rune() <= '9' {
+		// Alt+<n> jumps to the nth breadcrumb level, Alt+0 goes home
+		nav.jumpToLevel(int(event.Rune() - '0'))
+		return nil
+	}
+
+	action, ok := nav.keymap.action(event)
+	if !ok {
+		return event
+	}
+
+	switch action {
+	case actionBack:
+		if nav.live != nil {
+			// Close the live table before leaving any menu
+			nav.stopLiveWatch()
+			return nil
+		}
+		if nav.logStream != nil {
+			nav.stopLogStream()
+			return nil
+		}
+		if nav.document != nil {
+			nav.closeDocument()
+			return nil
+		}
+		if nav.popMenu() {
+			// Went back to previous menu
 			return nil
 		} else {
 			nav.app.Stop()
 		}
-	case tcell.KeyCtrlC:
+	case actionQuit:
 		nav.app.Stop()
-	case tcell.KeyCtrlR:
-		nav.getCurrentContext()
-		nav.getCurrentProject()
-		nav.updateStatusBar()
-	case tcell.KeyCtrlH:
+	case actionRefresh:
+		nav.sessionChanged()
+	case actionHistory:
 		nav.showCommandHistory()
 		return nil
-	case tcell.KeyCtrlX:
+	case actionNotifications:
+		nav.showNotifications()
+		return nil
+	case actionLogin:
+		nav.showLoginDialog("")
+		return nil
+	case actionExplain:
+		nav.explainCurrentKind()
+		return nil
+	case actionQuery:
+		nav.showQueryBox()
+		return nil
+	case actionFilter:
+		nav.showFilterPrompt()
+		return nil
+	case actionClearFilter:
+		nav.clearFilter()
+		return nil
+	case actionCustomCommand:
 		nav.showCustomCommandDialog()
 		return nil
+	case actionWatch:
+		nav.toggleWatch()
+		return nil
+	case actionLiveWatch:
+		nav.toggleLiveWatch()
+		return nil
+	case actionPalette:
+		nav.showCommandPalette()
+		return nil
+	case actionPaneGrow:
+		nav.resizeFocusedPane(1)
+		return nil
+	case actionPaneShrink:
+		nav.resizeFocusedPane(-1)
+		return nil
+	case actionZoom:
+		nav.toggleZoom()
+		return nil
+	case actionLayout:
+		nav.cycleLayoutMode()
+		return nil
+	case actionFocusNext:
+		nav.cycleFocus(1)
+		return nil
+	case actionFocusPrev:
+		nav.cycleFocus(-1)
+		return nil
+	case actionFavorite, actionFavoriteUp, actionFavoriteDown, actionFavoriteScope:
+		if nav.app.GetFocus() == nav.menuList && nav.handleFavoriteAction(action) {
+			return nil
+		}
 	}
 	return event
 }
 
-func (nav *OCNavigator) setStatus(message string) {
-	nav.updateStatusBar()
-	// Flash the message briefly
-	go func() {
-		originalStatus := nav.statusBar.GetText(false)
-		nav.statusBar.SetText(fmt.Sprintf("[yellow]%s[white]", message))
-		time.Sleep(2 * time.Second)
-		nav.statusBar.SetText(originalStatus)
-	}()
-}
-
 func (nav *OCNavigator) updateStatusBar() {
-	status := fmt.Sprintf(" Context: [cyan]%s[white] | Project: [green]%s[white] | ESC: Back | Ctrl+C: Quit | Ctrl+H: History | Ctrl+X: Custom | Ctrl+R: Refresh ",
-		nav.currentContext, nav.currentProject)
+	keys := nav.keymap
+	// What commands run with is shown first so that a narrow terminal does
+	// not cut it off, and a notification never hides it
+	status := ""
+	if nav.live != nil {
+		if nav.live.connected {
+			status = nav.theme.paint(roleLive, "● LIVE")
+		} else {
+			status = nav.theme.paint(roleWarning, "○ RECONNECTING")
+		}
+	}
+	if !nav.filter.empty() {
+		status += fmt.Sprintf(" Filter: %s (%s clears) |", nav.theme.paint(roleHighlight, tview.Escape(nav.filter.flags())), keys.label(actionClearFilter))
+	}
+	session := fmt.Sprintf(" Context: %s | Project: %s ", nav.theme.paint(roleContext, nav.currentContext), nav.theme.paint(roleProject, nav.currentProject))
+
+	// A notification takes the place of the key hints
+	if n, ok := nav.notifications.active(); ok {
+		nav.statusBar.SetText(status + " " + nav.paintNotification(n, false) + " |" + session)
+		return
+	}
+	status += nav.healthStatus() + session + fmt.Sprintf("| %s: Help | %s: Back | %s: Quit | %s: History | %s: Custom | %s: Watch | %s: Live | %s: Palette | %s: Refresh ",
+		keys.label(actionHelp), keys.label(actionBack), keys.label(actionQuit), keys.label(actionHistory), keys.label(actionCustomCommand),
+		keys.label(actionWatch), keys.label(actionLiveWatch), keys.label(actionPalette), keys.label(actionRefresh))
 	nav.statusBar.SetText(status)
 }
 
 func (nav *OCNavigator) Run() error {
-	return nav.app.Run()
+	err := nav.app.Run()
+
+	// Make sure no background oc process outlives the UI
+	nav.stopWatch()
+	nav.stopLiveWatch()
+	nav.stopLogStream()
+	nav.stopHealthCheck()
+	return err
 }
 
 // executeCLICommand runs an external command (like oc) and prints its output to stdout/stderr.
-func executeCLICommand(command string, args ...string) error {
-	cmd := exec.Command(command, args...)
+func executeCLICommand(tool cliTool, args ...string) error {
+	command := tool.display("oc " + strings.Join(args, " "))
+	cmd, err := tool.command(context.Background(), args...)
+	if err != nil {
+		return err
+	}
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	fmt.Printf("Executing: %s %s\n", command, strings.Join(args, " "))
-	err := cmd.Run()
+	fmt.Printf("Executing: %s\n", command)
+	err = cmd.Run()
 	if err != nil {
-		return fmt.Errorf("failed to execute command '%s %s': %w", command, strings.Join(args, " "), err)
+		return fmt.Errorf("failed to execute command '%s': %w", command, err)
 	}
 	return nil
 }
 
 func main() {
-	// Check if oc command is available
-	if _, err := exec.LookPath("oc"); err != nil {
-		fmt.Println("Error: 'oc' command not found. Please install OpenShift CLI.")
-		os.Exit(1)
-	}
-
 	switchProjectName := flag.String("project", "", "Switch to the specified OpenShift project before starting UI")
 	createProjectName := flag.String("create-project", "", "Create a new OpenShift project with the given name and exit")
 	deleteProjectName := flag.String("delete-project", "", "Delete an OpenShift project with the given name and exit")
+	menuPath := flag.String("menu", "", "Open the given menu path on startup, e.g. \"Workloads/Pods\"")
+	watchInterval := flag.Duration("watch-interval", defaultWatchInterval, "Interval between refreshes when watch mode (Ctrl+W) is active")
+	cliName := flag.String("cli", "oc", "Command line tool to run: oc, kubectl or a path to either")
 
 	flag.Parse()
 
+	// Check if the command line tool is available
+	explicit := false
+	flag.Visit(func(f *flag.Flag) { explicit = explicit || f.Name == "cli" })
+	tool, err := findCLI(*cliName, explicit)
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+
 	if *createProjectName != "" {
 		fmt.Printf("Attempting to create project: %s\n", *createProjectName)
-		err := executeCLICommand("oc", "new-project", *createProjectName)
+		err := executeCLICommand(tool, "new-project", *createProjectName)
 		if err != nil {
 			log.Fatalf("Error creating project '%s': %v", *createProjectName, err)
 		}
@@ -624,7 +1055,7 @@ func main() {
 
 	if *deleteProjectName != "" {
 		fmt.Printf("Attempting to delete project: %s\n", *deleteProjectName)
-		err := executeCLICommand("oc", "delete", "project", *deleteProjectName)
+		err := executeCLICommand(tool, "delete", "project", *deleteProjectName)
 		if err != nil {
 			log.Fatalf("Error deleting project '%s': %v", *deleteProjectName, err)
 		}
@@ -634,7 +1065,7 @@ func main() {
 
 	if *switchProjectName != "" {
 		fmt.Printf("Attempting to switch to project: %s\n", *switchProjectName)
-		err := executeCLICommand("oc", "project", *switchProjectName)
+		err := executeCLICommand(tool, "project", *switchProjectName)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error switching to project '%s': %v. Starting TUI with current project.\n", *switchProjectName, err)
 		} else {
@@ -642,7 +1073,15 @@ func main() {
 		}
 	}
 
-	navigator := NewOCNavigator()
+	navigator := NewOCNavigator(tool)
+	if *watchInterval > 0 {
+		navigator.watchInterval = *watchInterval
+	}
+	if *menuPath != "" {
+		if err := navigator.openMenuPath(*menuPath); err != nil {
+			log.Fatalf("Error opening menu '%s': %v", *menuPath, err)
+		}
+	}
 	if err := navigator.Run(); err != nil {
 		log.Fatalf("Error running oc-navigator: %v", err)
 	}
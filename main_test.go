@@ -0,0 +1,127 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// fakeOC writes script as an executable oc into a temporary directory and
+// returns its path.
+func fakeOC(t *testing.T, script string) string {
+	t.Helper()
+	oc := filepath.Join(t.TempDir(), "oc")
+	if err := os.WriteFile(oc, []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return oc
+}
+
+// newTestNavigator returns a navigator that runs script as oc, with the
+// config and cache kept in a temporary directory. The application is not
+// run, so updates queued from the background are never applied.
+func newTestNavigator(t *testing.T, script string) *OCNavigator {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("OC_NAVIGATOR_CONFIG", filepath.Join(dir, "config.json"))
+	t.Setenv("XDG_CACHE_HOME", dir)
+	t.Setenv("NO_COLOR", "")
+	return NewOCNavigator(cliTool{path: fakeOC(t, script)})
+}
+
+// pressKeys sends keys to the focused primitive, as the application would.
+func pressKeys(nav *OCNavigator, keys ...*tcell.EventKey) {
+	for _, key := range keys {
+		nav.app.GetFocus().InputHandler()(key, func(p tview.Primitive) { nav.app.SetFocus(p) })
+	}
+}
+
+// typeText types text into the focused primitive.
+func typeText(nav *OCNavigator, text string) {
+	for _, r := range text {
+		pressKeys(nav, tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+}
+
+// unauthorizedOC answers the startup queries and rejects everything else
+// as an expired session.
+const unauthorizedOC = `case "$*" in
+  "config current-context") echo test;;
+  "project -q") echo demo;;
+  *) echo "error: You must be logged in to the server (Unauthorized)" >&2; exit 1;;
+esac
+`
+
+func TestDialogCommandsKeepTheLoginForm(t *testing.T) {
+	enter := tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)
+	tab := tcell.NewEventKey(tcell.KeyTab, 0, tcell.ModNone)
+	tests := []struct {
+		name string
+		run  func(nav *OCNavigator)
+	}{
+		{"switch project", func(nav *OCNavigator) {
+			nav.showProjectSwitchDialog()
+			typeText(nav, "prod")
+			pressKeys(nav, tab, enter)
+		}},
+		{"create project", func(nav *OCNavigator) {
+			nav.showCreateProjectDialog()
+			typeText(nav, "prod")
+			pressKeys(nav, tab, tab, enter)
+		}},
+		{"delete project", func(nav *OCNavigator) {
+			nav.showDeleteConfirmationDialog("prod")
+			pressKeys(nav, enter)
+		}},
+		{"pod logs", func(nav *OCNavigator) {
+			nav.showPodLogsDialog()
+			typeText(nav, "web-1")
+			pressKeys(nav, tab, enter)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nav := newTestNavigator(t, unauthorizedOC)
+			tt.run(nav)
+			form, ok := nav.overlay.(*tview.Form)
+			if !ok {
+				t.Fatalf("overlay = %T, want the login form", nav.overlay)
+			}
+			if title := form.GetTitle(); title != " Session expired: log in again " {
+				t.Errorf("overlay title = %q, want the login form", title)
+			}
+			if front, _ := nav.pages.GetFrontPage(); front != "overlay" {
+				t.Errorf("front page = %q, want the overlay", front)
+			}
+		})
+	}
+}
+
+func TestExecuteCommandClearsSearch(t *testing.T) {
+	nav := newTestNavigator(t, `case "$*" in
+  "get pods") printf 'web-1 Running\nweb-2 Running\ndb-1 Running\n';;
+  *) echo test;;
+esac
+`)
+	nav.executeCommand("oc get pods")
+	nav.searchText("web")
+	nav.nextMatch(0)
+	if len(nav.commandView.GetHighlights()) == 0 {
+		t.Fatal("no match highlighted after the search")
+	}
+
+	nav.executeCommand("oc get pods")
+	if nav.search != nil {
+		t.Errorf("search = %+v after new output, want none", nav.search)
+	}
+	if got := nav.commandView.GetHighlights(); len(got) != 0 {
+		t.Errorf("highlights = %v after new output, want none", got)
+	}
+	nav.nextMatch(1)
+	if got := nav.commandView.GetHighlights(); len(got) != 0 {
+		t.Errorf("highlights = %v after n, want none", got)
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// focusPanes returns the panes that Tab cycles through, in order.
+func (nav *OCNavigator) focusPanes() []tview.Primitive {
+	output := tview.Primitive(nav.commandView)
+	if nav.live != nil {
+		output = nav.resourceTable
+	} else if nav.document != nil {
+		output = nav.documentTree
+	}
+	return []tview.Primitive{nav.menuList, nav.detailView, output}
+}
+
+// cycleFocus moves focus to the next (1) or previous (-1) pane.
+func (nav *OCNavigator) cycleFocus(direction int) {
+	panes := nav.focusPanes()
+	current := 0
+	for i, pane := range panes {
+		if pane == nav.app.GetFocus() {
+			current = i
+		}
+	}
+	nav.app.SetFocus(panes[(current+direction+len(panes))%len(panes)])
+
+	// A zoomed layout follows the focus
+	if nav.zoomed != nil {
+		nav.zoomed = nav.focusedPane()
+		nav.applyLayout()
+	}
+}
+
+// handleMenuMouse makes a click select a menu item and a double click open
+// or run it, like Enter.
+func (nav *OCNavigator) handleMenuMouse(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+	if action != tview.MouseLeftClick && action != tview.MouseLeftDoubleClick {
+		return action, event
+	}
+
+	index := nav.menuIndexAt(event.Position())
+	if index < 0 {
+		return action, event
+	}
+
+	nav.app.SetFocus(nav.menuList)
+	nav.menuList.SetCurrentItem(index)
+	if action == tview.MouseLeftDoubleClick {
+		item := nav.currentMenu[index]
+		nav.onMenuSelect(index, item.Name, item.Description, 0)
+	}
+	return tview.MouseConsumed, nil
+}
+
+// menuIndexAt returns the menu item shown at the screen position, or -1.
+func (nav *OCNavigator) menuIndexAt(x, y int) int {
+	left, top, width, height := nav.menuList.GetInnerRect()
+	if x < left || x >= left+width || y < top || y >= top+height {
+		return -1
+	}
+
+	// Every item takes two lines with its description
+	offset, _ := nav.menuList.GetOffset()
+	index := offset + (y-top)/2
+	if index >= len(nav.currentMenu) {
+		return -1
+	}
+	return index
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// notifyLevel is the severity of a notification.
+type notifyLevel string
+
+const (
+	notifyInfo    notifyLevel = "info"
+	notifySuccess notifyLevel = "success"
+	notifyWarn    notifyLevel = "warn"
+	notifyError   notifyLevel = "error"
+)
+
+// defaultNotifyTimeouts is how long each severity stays in the status bar.
+var defaultNotifyTimeouts = map[notifyLevel]time.Duration{
+	notifyInfo:    3 * time.Second,
+	notifySuccess: 3 * time.Second,
+	notifyWarn:    5 * time.Second,
+	notifyError:   10 * time.Second,
+}
+
+// maxNotifications bounds the history kept for the notifications view.
+const maxNotifications = 200
+
+type notification struct {
+	level   notifyLevel
+	message string
+	time    time.Time
+}
+
+// notifier queues notifications from any goroutine. The status bar shows the
+// newest one until its timeout expires or a newer one replaces it.
+type notifier struct {
+	mu       sync.Mutex
+	history  []notification
+	current  *notification
+	seq      int
+	timeouts map[notifyLevel]time.Duration
+}
+
+// newNotifier applies the configured timeouts on top of the defaults and
+// reports any that cannot be used.
+func newNotifier(overrides map[string]string) (*notifier, []string) {
+	n := &notifier{timeouts: make(map[notifyLevel]time.Duration, len(defaultNotifyTimeouts))}
+	for level, timeout := range defaultNotifyTimeouts {
+		n.timeouts[level] = timeout
+	}
+
+	var problems []string
+	for name, value := range overrides {
+		level := notifyLevel(name)
+		if _, ok := defaultNotifyTimeouts[level]; !ok {
+			problems = append(problems, fmt.Sprintf("unknown notification level %q", name))
+			continue
+		}
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout < 0 {
+			problems = append(problems, fmt.Sprintf("invalid %s timeout %q", name, value))
+			continue
+		}
+		n.timeouts[level] = timeout
+	}
+	sort.Strings(problems)
+	return n, problems
+}
+
+// push records a notification and makes it the current one. It returns the
+// sequence number that identifies it for expire.
+func (n *notifier) push(level notifyLevel, message string) (int, time.Duration) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.history = append(n.history, notification{level: level, message: message, time: time.Now()})
+	if len(n.history) > maxNotifications {
+		n.history = n.history[len(n.history)-maxNotifications:]
+	}
+	n.current = &n.history[len(n.history)-1]
+	n.seq++
+	return n.seq, n.timeouts[level]
+}
+
+// expire clears the current notification if it is still the one numbered seq.
+func (n *notifier) expire(seq int) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.seq != seq || n.current == nil {
+		return false
+	}
+	n.current = nil
+	return true
+}
+
+// active returns the notification to show in the status bar, if any.
+func (n *notifier) active() (notification, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.current == nil {
+		return notification{}, false
+	}
+	return *n.current, true
+}
+
+// recent returns the history, newest first.
+func (n *notifier) recent() []notification {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	recent := make([]notification, len(n.history))
+	for i, entry := range n.history {
+		recent[len(n.history)-1-i] = entry
+	}
+	return recent
+}
+
+// notify shows a message in the status bar. It is safe to call from any
+// goroutine: the status bar is only touched from the event loop.
+func (nav *OCNavigator) notify(level notifyLevel, message string) {
+	seq, timeout := nav.notifications.push(level, message)
+	go nav.app.QueueUpdateDraw(nav.updateStatusBar)
+	if timeout > 0 {
+		time.AfterFunc(timeout, func() {
+			if nav.notifications.expire(seq) {
+				nav.app.QueueUpdateDraw(nav.updateStatusBar)
+			}
+		})
+	}
+}
+
+// levelRole returns the style role used for a severity.
+func levelRole(level notifyLevel) styleRole {
+	switch level {
+	case notifySuccess:
+		return roleSuccess
+	case notifyWarn:
+		return roleWarning
+	case notifyError:
+		return roleError
+	}
+	return roleTitle
+}
+
+// paintNotification renders a notification for a dynamic-color text.
+func (nav *OCNavigator) paintNotification(n notification, withLevel bool) string {
+	text := tview.Escape(n.message)
+	if withLevel {
+		text = fmt.Sprintf("%-7s %s", strings.ToUpper(string(n.level)), text)
+	}
+	return nav.theme.paint(levelRole(n.level), text)
+}
+
+// showNotifications lists recent notifications, newest first.
+func (nav *OCNavigator) showNotifications() {
+	list := tview.NewList().ShowSecondaryText(false)
+	recent := nav.notifications.recent()
+	for _, n := range recent {
+		list.AddItem(n.time.Format("15:04:05")+" "+nav.paintNotification(n, true), "", 0, nil)
+	}
+	if len(recent) == 0 {
+		list.AddItem("No notifications", "", 0, nil)
+	}
+
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			nav.closeOverlay()
+			return nil
+		}
+		return event
+	})
+	list.SetBorder(true).
+		SetTitle(" Notifications (ESC: close) ").
+		SetTitleAlign(tview.AlignLeft)
+	nav.showOverlay(list)
+}
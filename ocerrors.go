@@ -0,0 +1,169 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// errorCategory is the kind of failure reported by oc.
+type errorCategory string
+
+const (
+	errNotFound        errorCategory = "NotFound"
+	errForbidden       errorCategory = "Forbidden"
+	errUnauthorized    errorCategory = "Unauthorized"
+	errConflict        errorCategory = "Conflict"
+	errAlreadyExists   errorCategory = "AlreadyExists"
+	errConnection      errorCategory = "ConnectionRefused"
+	errUnknownResource errorCategory = "UnknownResourceType"
+	errMissingCRD      errorCategory = "MissingCRD"
+	errUncategorized   errorCategory = ""
+)
+
+// commandError is a failed command with its stderr classified into a
+// category, a one line summary and a suggested next step.
+type commandError struct {
+	exitCode int
+	stderr   string
+	category errorCategory
+	summary  string
+	hint     string
+}
+
+func (e *commandError) Error() string {
+	if e.summary != "" {
+		return e.summary
+	}
+	if line, _, _ := strings.Cut(strings.TrimSpace(e.stderr), "\n"); line != "" {
+		return line
+	}
+	return fmt.Sprintf("exit status %d", e.exitCode)
+}
+
+// crdKinds are resource types that only exist when an add-on installs their
+// CRDs, with the kind and the add-on to name in the suggestion.
+var crdKinds = map[string][2]string{
+	"volumesnapshots":         {"VolumeSnapshot", "the CSI snapshot controller"},
+	"volumesnapshotclasses":   {"VolumeSnapshotClass", "the CSI snapshot controller"},
+	"servicemonitors":         {"ServiceMonitor", "the Prometheus operator"},
+	"podmonitors":             {"PodMonitor", "the Prometheus operator"},
+	"prometheusrules":         {"PrometheusRule", "the Prometheus operator"},
+	"certificates":            {"Certificate", "cert-manager"},
+	"issuers":                 {"Issuer", "cert-manager"},
+	"virtualmachines":         {"VirtualMachine", "OpenShift Virtualization"},
+	"virtualmachineinstances": {"VirtualMachineInstance", "OpenShift Virtualization"},
+	"routes":                  {"Route", "OpenShift"},
+	"deploymentconfigs":       {"DeploymentConfig", "OpenShift"},
+	"dc":                      {"DeploymentConfig", "OpenShift"},
+	"buildconfigs":            {"BuildConfig", "OpenShift"},
+	"bc":                      {"BuildConfig", "OpenShift"},
+	"imagestreams":            {"ImageStream", "OpenShift"},
+	"is":                      {"ImageStream", "OpenShift"},
+	"clusterversion":          {"ClusterVersion", "OpenShift"},
+	"co":                      {"ClusterOperator", "OpenShift"},
+	"mcp":                     {"MachineConfigPool", "OpenShift"},
+	"subscriptions":           {"Subscription", "the Operator Lifecycle Manager"},
+	"csv":                     {"ClusterServiceVersion", "the Operator Lifecycle Manager"},
+}
+
+var (
+	notFoundPattern     = regexp.MustCompile(`\(NotFound\): (.*)`)
+	forbiddenPattern    = regexp.MustCompile(`cannot (\w+) resource "([^"]+)"(?: in API group "[^"]*")?(?: in the namespace "([^"]+)")?`)
+	resourceTypePattern = regexp.MustCompile(`doesn't have a resource type "([^"]+)"`)
+	noKindPattern       = regexp.MustCompile(`no matches for kind "([^"]+)" in version "([^"]+)"`)
+)
+
+// classifyError turns a failed command into a commandError. Errors that are
+// not exit failures, such as a missing binary, are returned unchanged.
+func classifyError(err error, stderr string) error {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return err
+	}
+
+	e := &commandError{exitCode: exitErr.ExitCode(), stderr: stderr}
+	lower := strings.ToLower(stderr)
+	switch {
+	case strings.Contains(stderr, "(Unauthorized)"), strings.Contains(lower, "must be logged in"),
+		strings.Contains(lower, "token has expired"):
+		e.category = errUnauthorized
+		e.summary = "You are not logged in, or your token has expired"
+		e.hint = "Run login? Once you are logged in again the command is retried."
+	case strings.Contains(stderr, "(Forbidden)"):
+		e.category = errForbidden
+		e.summary = "You are not allowed to do this"
+		if m := forbiddenPattern.FindStringSubmatch(stderr); m != nil {
+			e.summary = fmt.Sprintf("You are not allowed to %s %s", m[1], m[2])
+			if m[3] != "" {
+				e.summary += " in project " + m[3]
+			}
+		}
+		e.hint = "Switch project? Otherwise ask a cluster admin for a role that grants this."
+	case strings.Contains(stderr, "(NotFound)"):
+		e.category = errNotFound
+		e.summary = "The resource was not found"
+		if m := notFoundPattern.FindStringSubmatch(stderr); m != nil {
+			e.summary = strings.TrimSpace(m[1])
+		}
+		e.hint = "Check the name, or switch project? It may live in another namespace."
+	case strings.Contains(stderr, "(AlreadyExists)"):
+		e.category = errAlreadyExists
+		e.summary = "A resource with this name already exists"
+		e.hint = "Pick another name, or edit the existing resource instead."
+	case strings.Contains(stderr, "(Conflict)"):
+		e.category = errConflict
+		e.summary = "The resource was changed by someone else since it was read"
+		e.hint = "Reload it and apply the change again."
+	case strings.Contains(lower, "connection refused"), strings.Contains(lower, "unable to connect to the server"),
+		strings.Contains(lower, "no such host"), strings.Contains(lower, "i/o timeout"),
+		strings.Contains(lower, "tls handshake timeout"), strings.Contains(lower, "network is unreachable"),
+		strings.Contains(lower, "the connection to the server") && strings.Contains(lower, "was refused"):
+		e.category = errConnection
+		e.summary = "The API server cannot be reached"
+		e.hint = "Check your VPN or network and the server URL, or log in to another cluster."
+	case resourceTypePattern.MatchString(stderr):
+		kind := resourceTypePattern.FindStringSubmatch(stderr)[1]
+		if crd, ok := crdKinds[strings.ToLower(kind)]; ok {
+			e.category = errMissingCRD
+			e.summary = fmt.Sprintf("This cluster lacks %s CRDs", crd[0])
+			e.hint = fmt.Sprintf("%s is provided by %s, which is not installed here.", crd[0], crd[1])
+		} else {
+			e.category = errUnknownResource
+			e.summary = fmt.Sprintf("Unknown resource type %q", kind)
+			e.hint = "Check the spelling; \"oc api-resources\" lists the types this cluster knows."
+		}
+	case noKindPattern.MatchString(stderr):
+		m := noKindPattern.FindStringSubmatch(stderr)
+		e.category = errMissingCRD
+		e.summary = fmt.Sprintf("This cluster lacks %s CRDs (%s)", m[1], m[2])
+		e.hint = "Install the operator that provides this kind, or check the apiVersion."
+	}
+	return e
+}
+
+// writeCommandError writes the summary and suggestion of err, followed by
+// the exit code and raw stderr in a section of their own.
+func (nav *OCNavigator) writeCommandError(w io.Writer, err error) {
+	var e *commandError
+	if !errors.As(err, &e) {
+		fmt.Fprintf(w, "%s\n\n", nav.theme.paint(roleError, tview.Escape(fmt.Sprintf("Error: %v", err))))
+		return
+	}
+
+	if e.category != errUncategorized {
+		fmt.Fprintf(w, "%s\n", nav.theme.paint(roleError, fmt.Sprintf("✗ %s: %s", e.category, tview.Escape(e.summary))))
+		fmt.Fprintf(w, "%s\n\n", nav.theme.paint(roleSuccess, "→ "+tview.Escape(e.hint)))
+	} else {
+		fmt.Fprintf(w, "%s\n\n", nav.theme.paint(roleError, "✗ Command failed"))
+	}
+	fmt.Fprintf(w, "%s\n", nav.theme.paint(roleMuted, fmt.Sprintf("── exit code %d, stderr ──", e.exitCode)))
+	if stderr := strings.TrimRight(e.stderr, "\n"); stderr != "" {
+		fmt.Fprintf(w, "%s\n", tview.Escape(stderr))
+	}
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// exitError returns the *exec.ExitError of a command that exits with 1.
+func exitError(t *testing.T) error {
+	t.Helper()
+	err := exec.Command("sh", "-c", "exit 1").Run()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("sh did not exit with an error: %v", err)
+	}
+	return err
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		stderr   string
+		category errorCategory
+		summary  string
+	}{
+		{
+			stderr:   "error: You must be logged in to the server (Unauthorized)",
+			category: errUnauthorized,
+			summary:  "You are not logged in, or your token has expired",
+		},
+		{
+			stderr:   `Error from server (Forbidden): pods is forbidden: User "dev" cannot list resource "pods" in API group "" in the namespace "prod"`,
+			category: errForbidden,
+			summary:  "You are not allowed to list pods in project prod",
+		},
+		{
+			stderr:   `Error from server (NotFound): pods "web-1" not found`,
+			category: errNotFound,
+			summary:  `pods "web-1" not found`,
+		},
+		{
+			stderr:   `Error from server (AlreadyExists): services "web" already exists`,
+			category: errAlreadyExists,
+		},
+		{
+			stderr:   "Error from server (Conflict): the object has been modified",
+			category: errConflict,
+		},
+		{
+			stderr:   "The connection to the server api.example.com:6443 was refused - did you specify the right host or port?\n",
+			category: errConnection,
+			summary:  "The API server cannot be reached",
+		},
+		{
+			stderr:   "Unable to connect to the server: dial tcp: lookup api.example.com: no such host",
+			category: errConnection,
+		},
+		{
+			stderr:   "Unable to connect to the server: net/http: TLS handshake timeout",
+			category: errConnection,
+		},
+		{
+			stderr:   `error: the server doesn't have a resource type "routes"`,
+			category: errMissingCRD,
+			summary:  "This cluster lacks Route CRDs",
+		},
+		{
+			stderr:   `error: the server doesn't have a resource type "pdos"`,
+			category: errUnknownResource,
+			summary:  `Unknown resource type "pdos"`,
+		},
+		{
+			stderr:   `error: unable to recognize "app.yaml": no matches for kind "Widget" in version "example.com/v1"`,
+			category: errMissingCRD,
+			summary:  "This cluster lacks Widget CRDs (example.com/v1)",
+		},
+		{
+			stderr:   "error: something else went wrong\nwith details",
+			category: errUncategorized,
+			summary:  "error: something else went wrong",
+		},
+	}
+	exitErr := exitError(t)
+	for _, tt := range tests {
+		err := classifyError(exitErr, tt.stderr)
+		var e *commandError
+		if !errors.As(err, &e) {
+			t.Fatalf("classifyError(%q) = %T, want *commandError", tt.stderr, err)
+		}
+		if e.category != tt.category {
+			t.Errorf("classifyError(%q) category = %q, want %q", tt.stderr, e.category, tt.category)
+		}
+		if tt.summary != "" && e.Error() != tt.summary {
+			t.Errorf("classifyError(%q) = %q, want %q", tt.stderr, e.Error(), tt.summary)
+		}
+		if e.exitCode != 1 {
+			t.Errorf("classifyError(%q) exit code = %d, want 1", tt.stderr, e.exitCode)
+		}
+	}
+}
+
+func TestClassifyErrorKeepsOtherErrors(t *testing.T) {
+	err := errors.New(`exec: "oc": executable file not found in $PATH`)
+	if got := classifyError(err, ""); got != err {
+		t.Errorf("classifyError() = %v, want the error unchanged", got)
+	}
+}
+
+func TestConnStateOf(t *testing.T) {
+	exitErr := exitError(t)
+	tests := []struct {
+		name  string
+		err   error
+		state connState
+		msg   string
+	}{
+		{
+			name:  "unauthorized",
+			err:   classifyError(exitErr, "error: You must be logged in to the server (Unauthorized)\n"),
+			state: connUnauthorized,
+			msg:   "error: You must be logged in to the server (Unauthorized)",
+		},
+		{
+			name:  "expired token",
+			err:   classifyError(exitErr, "error: the token has expired"),
+			state: connUnauthorized,
+		},
+		{
+			name:  "unreachable",
+			err:   classifyError(exitErr, "Unable to connect to the server: dial tcp 10.0.0.1:6443: connect: network is unreachable"),
+			state: connUnreachable,
+		},
+		{
+			name:  "timeout",
+			err:   fmt.Errorf("timed out after 10s: %w", context.DeadlineExceeded),
+			state: connUnreachable,
+			msg:   "timed out after 10s: context deadline exceeded",
+		},
+		{
+			name:  "other",
+			err:   classifyError(exitErr, "error: unexpected response"),
+			state: connError,
+			msg:   "error: unexpected response",
+		},
+	}
+	for _, tt := range tests {
+		state, msg := connStateOf(tt.err)
+		if state != tt.state {
+			t.Errorf("%s: state = %v, want %v", tt.name, state, tt.state)
+		}
+		if tt.msg != "" && msg != tt.msg {
+			t.Errorf("%s: message = %q, want %q", tt.name, msg, tt.msg)
+		}
+		if strings.TrimSpace(msg) == "" {
+			t.Errorf("%s: empty message", tt.name)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// paletteEntry is a menu item reachable from the command palette together
+// with the index path that leads to it from the main menu.
+type paletteEntry struct {
+	item    *MenuItem
+	path    []int
+	trail   string
+	command string // set for raw resource kinds that have no menu item
+	nth     int    // set for ":<n>", selects the nth item of the current menu
+}
+
+// commandPalette is the fuzzy finder opened with ':' or Ctrl+P.
+type commandPalette struct {
+	input   *tview.InputField
+	list    *tview.List
+	entries []paletteEntry
+	matches []paletteEntry
+}
+
+// menuEntries flattens the menu tree rooted at menu into palette entries.
+func menuEntries(menu []*MenuItem, path []int, trail []string) []paletteEntry {
+	var entries []paletteEntry
+	for i, item := range menu {
+		itemPath := append(append([]int{}, path...), i)
+		itemTrail := append(append([]string{}, trail...), item.Name)
+		entries = append(entries, paletteEntry{
+			item:  item,
+			path:  itemPath,
+			trail: strings.Join(itemTrail, " › "),
+		})
+		if item.Submenu != nil {
+			entries = append(entries, menuEntries(item.Submenu, itemPath, itemTrail)...)
+		}
+	}
+	return entries
+}
+
+// fuzzyScore reports whether all runes of pattern appear in text in order,
+// and scores the match higher for consecutive runes and word starts.
+func fuzzyScore(pattern, text string) (int, bool) {
+	if pattern == "" {
+		return 0, true
+	}
+
+	p := []rune(strings.ToLower(pattern))
+	t := []rune(strings.ToLower(text))
+	score, pi, last := 0, 0, -2
+	for ti := 0; ti < len(t) && pi < len(p); ti++ {
+		if t[ti] != p[pi] {
+			continue
+		}
+		score++
+		if ti == last+1 {
+			score += 2
+		}
+		if ti == 0 || !unicode.IsLetter(t[ti-1]) {
+			score += 3
+		}
+		last = ti
+		pi++
+	}
+	return score, pi == len(p)
+}
+
+// score rates an entry against query, preferring matches on the item name.
+func (e paletteEntry) score(query string) (int, bool) {
+	best, matched := 0, false
+	fields := []struct {
+		text   string
+		weight int
+	}{
+		{e.item.Name, 3},
+		{e.trail, 2},
+		{e.item.Command, 2},
+		{e.item.Description, 1},
+	}
+	for _, field := range fields {
+		if s, ok := fuzzyScore(query, field.text); ok && s*field.weight > best {
+			best, matched = s*field.weight, true
+		}
+	}
+	// A raw kind like "pods" should land on the item that lists exactly that
+	if e.item.Command != "" && strings.EqualFold(strings.TrimPrefix(e.item.Command, "oc get "), query) {
+		best += 1000
+	}
+	return best, matched
+}
+
+// showCommandPalette opens a fuzzy search over every menu item at any depth.
+func (nav *OCNavigator) showCommandPalette() {
+	p := &commandPalette{
+		input:   tview.NewInputField().SetLabel("> ").SetFieldBackgroundColor(tcell.ColorDefault),
+		list:    tview.NewList().ShowSecondaryText(true).SetHighlightFullLine(true),
+		entries: menuEntries(nav.rootMenu, nil, nil),
+	}
+	nav.palette = p
+
+	p.input.SetChangedFunc(func(text string) {
+		nav.filterPalette(text)
+	})
+	p.input.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			nav.closeCommandPalette()
+			return nil
+		case tcell.KeyEnter:
+			nav.runPaletteEntry(true)
+			return nil
+		case tcell.KeyTab:
+			nav.runPaletteEntry(false)
+			return nil
+		case tcell.KeyUp, tcell.KeyDown, tcell.KeyPgUp, tcell.KeyPgDn:
+			p.list.InputHandler()(event, nil)
+			return nil
+		}
+		return event
+	})
+
+	help := tview.NewTextView().SetDynamicColors(true).
+		SetText(fmt.Sprintf("%s: run  %s: jump to item  %s: close  (type a kind like %s to list it, or a number to pick that item)",
+			nav.theme.paint(roleKey, "Enter"), nav.theme.paint(roleKey, "Tab"), nav.theme.paint(roleKey, "ESC"), nav.theme.paint(roleCommand, "pods")))
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(p.input, 1, 0, true).
+		AddItem(p.list, 0, 1, false).
+		AddItem(help, 1, 0, false)
+	layout.SetBorder(true).SetTitle(" Command Palette ").SetTitleAlign(tview.AlignLeft)
+
+	nav.filterPalette("")
+	nav.showOverlay(layout)
+	nav.app.SetFocus(p.input)
+}
+
+// filterPalette refreshes the palette list with the entries matching query,
+// best matches first.
+func (nav *OCNavigator) filterPalette(query string) {
+	p := nav.palette
+	query = strings.TrimSpace(query)
+
+	type scored struct {
+		entry paletteEntry
+		score int
+	}
+	var results []scored
+	for _, entry := range p.entries {
+		if s, ok := entry.score(query); ok {
+			results = append(results, scored{entry, s})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].score > results[j].score
+	})
+
+	p.matches = p.matches[:0]
+
+	// ":<n>" picks the nth item of the menu currently shown, ansible-navigator style
+	if n, err := strconv.Atoi(query); err == nil && n >= 1 && n <= len(nav.currentMenu) {
+		p.matches = append(p.matches, paletteEntry{
+			item:  nav.currentMenu[n-1],
+			trail: fmt.Sprintf("Item %d of%s", n, nav.menuList.GetTitle()),
+			nth:   n,
+		})
+	}
+
+	for _, r := range results {
+		p.matches = append(p.matches, r.entry)
+	}
+
+	// Single words are also offered as raw resource kinds, k9s style
+	if query != "" && !strings.ContainsAny(query, " \t") {
+		command := "oc get " + strings.ToLower(query)
+		p.matches = append(p.matches, paletteEntry{
+			item:    &MenuItem{Name: command, Description: "List resources of kind " + query, Command: command, IsExec: true},
+			trail:   "Resource kind",
+			command: command,
+		})
+	}
+
+	p.list.Clear()
+	for _, entry := range p.matches {
+		secondary := entry.trail
+		if entry.item.Command != "" {
+			secondary = fmt.Sprintf("%s  %s", entry.trail, nav.theme.paint(roleMuted, "("+entry.item.Command+")"))
+		}
+		p.list.AddItem(entry.item.Name, secondary, 0, nil)
+	}
+}
+
+// runPaletteEntry closes the palette and either runs the selected entry or
+// only moves the menu cursor onto it.
+func (nav *OCNavigator) runPaletteEntry(run bool) {
+	p := nav.palette
+	index := p.list.GetCurrentItem()
+	if index < 0 || index >= len(p.matches) {
+		return
+	}
+	entry := p.matches[index]
+	nav.closeCommandPalette()
+
+	if entry.command != "" {
+		nav.executeCommand(nav.filtered(entry.command))
+		return
+	}
+
+	if entry.nth > 0 {
+		nav.menuList.SetCurrentItem(entry.nth - 1)
+		if run {
+			nav.onMenuSelect(entry.nth-1, entry.item.Name, entry.item.Description, 0)
+		}
+		return
+	}
+
+	nav.navigateTo(entry.path)
+	if run {
+		selected := entry.path[len(entry.path)-1]
+		nav.onMenuSelect(selected, entry.item.Name, entry.item.Description, 0)
+	}
+}
+
+func (nav *OCNavigator) closeCommandPalette() {
+	nav.palette = nil
+	nav.closeOverlay()
+}
+
+// navigateTo rebuilds the menu stack so that the menu containing the item at
+// path (indices from the main menu) is shown with that item selected.
+func (nav *OCNavigator) navigateTo(path []int) {
+	nav.menuStack = nav.menuStack[:0]
+	nav.menuParents = nav.menuParents[:0]
+	nav.titleStack = nav.titleStack[:0]
+	nav.currentMenu = nav.rootMenu
+
+	title := " Navigation "
+	for _, index := range path[:len(path)-1] {
+		item := nav.currentMenu[index]
+		nav.menuStack = append(nav.menuStack, nav.currentMenu)
+		nav.menuParents = append(nav.menuParents, item)
+		nav.titleStack = append(nav.titleStack, title)
+		nav.currentMenu = item.Submenu
+		title = fmt.Sprintf(" %s ", item.Name)
+	}
+
+	nav.activeItem = nil
+	nav.populateMenu()
+	nav.menuList.SetTitle(title)
+	nav.menuList.SetCurrentItem(path[len(path)-1])
+	nav.updateBreadcrumb()
+}
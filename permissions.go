@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Permission display modes, set with "permissions" in the config.
+const (
+	permissionsDim  = "dim"
+	permissionsHide = "hide"
+	permissionsOff  = "off"
+)
+
+// accessRule is one line of "oc auth can-i --list".
+type accessRule struct {
+	resources []string
+	verbs     []string
+}
+
+// permissions are the rules of the current user in one context and project.
+type permissions struct {
+	context string
+	project string
+	rules   []accessRule
+}
+
+// shortNames maps the abbreviations used in the menu to resource names.
+var shortNames = map[string]string{
+	"po": "pods", "deploy": "deployments", "dc": "deploymentconfigs", "rs": "replicasets",
+	"sts": "statefulsets", "ds": "daemonsets", "cj": "cronjobs", "svc": "services",
+	"ing": "ingresses", "ep": "endpoints", "netpol": "networkpolicies", "pv": "persistentvolumes",
+	"pvc": "persistentvolumeclaims", "sc": "storageclasses", "cm": "configmaps", "sa": "serviceaccounts",
+	"bc": "buildconfigs", "is": "imagestreams", "istag": "imagestreamtags", "co": "clusteroperators",
+	"mcp": "machineconfigpools", "ns": "namespaces", "no": "nodes", "ev": "events",
+	"hpa": "horizontalpodautoscalers", "quota": "resourcequotas", "limits": "limitranges",
+	"clusterversion": "clusterversions", "ingress": "ingresses",
+}
+
+// canIPattern matches the resource and verb columns of "oc auth can-i --list".
+var canIPattern = regexp.MustCompile(`^(\S+)\s+\[[^\]]*\]\s+\[([^\]]*)\]\s+\[([^\]]*)\]\s*$`)
+
+// parseCanIList reads the rules from "oc auth can-i --list". Rules limited
+// to specific resource names are skipped since they never allow listing.
+func parseCanIList(output string) []accessRule {
+	var rules []accessRule
+	for _, line := range strings.Split(output, "\n") {
+		m := canIPattern.FindStringSubmatch(line)
+		if m == nil || m[2] != "" {
+			continue
+		}
+		rules = append(rules, accessRule{resources: strings.Split(m[1], ","), verbs: strings.Fields(m[3])})
+	}
+	return rules
+}
+
+// normalizeResource turns a kind as typed on the command line into the plural
+// resource name used by RBAC, without its API group.
+func normalizeResource(kind string) string {
+	kind = strings.ToLower(kind)
+	kind, _, _ = strings.Cut(kind, ".")
+	if name, ok := shortNames[kind]; ok {
+		return name
+	}
+	switch {
+	case strings.HasSuffix(kind, "ss"), strings.HasSuffix(kind, "x"):
+		return kind + "es"
+	case strings.HasSuffix(kind, "s"):
+		return kind
+	case strings.HasSuffix(kind, "y") && !strings.HasSuffix(kind, "ay"):
+		return strings.TrimSuffix(kind, "y") + "ies"
+	}
+	return kind + "s"
+}
+
+// requiredAccess returns the verb and resource a menu command needs. It
+// reports false for commands that are not checked, such as "oc whoami".
+func requiredAccess(command string) (verb, resource string, ok bool) {
+	fields := strings.Fields(command)
+	if len(fields) < 3 || fields[0] != "oc" || strings.HasPrefix(fields[2], "-") {
+		return "", "", false
+	}
+
+	kind := strings.Split(fields[2], ",")[0]
+	if kind == "all" {
+		return "", "", false
+	}
+	kind, name, hasName := strings.Cut(kind, "/")
+	named := hasName && name != "" || len(fields) > 3 && !strings.HasPrefix(fields[3], "-")
+
+	switch fields[1] {
+	case "get":
+		verb = "list"
+		if named {
+			verb = "get"
+		}
+	case "describe":
+		verb = "get"
+	case "delete":
+		verb = "delete"
+	case "edit":
+		verb = "update"
+	case "logs":
+		return "get", "pods/log", true
+	default:
+		return "", "", false
+	}
+	return verb, normalizeResource(kind), true
+}
+
+// allows reports whether the rules grant verb on resource.
+func (p *permissions) allows(verb, resource string) bool {
+	for _, rule := range p.rules {
+		if !matchesAny(rule.verbs, verb) {
+			continue
+		}
+		for _, r := range rule.resources {
+			if r == "*" || r == "*.*" {
+				return true
+			}
+			base, _, _ := strings.Cut(r, ".")
+			if base == resource {
+				return true
+			}
+			// "pods/log" is written as "pods/log" or covered by "pods/*"
+			if parent, sub, found := strings.Cut(resource, "/"); found && (base == parent+"/*" || base == parent+"/"+sub) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sameRules reports whether other holds the same rules, so that a refresh
+// that changes nothing does not disturb the menu. Two unknown permissions
+// are the same.
+func (p *permissions) sameRules(other *permissions) bool {
+	if p == nil || other == nil {
+		return p == other
+	}
+	if len(p.rules) != len(other.rules) {
+		return false
+	}
+	for i := range p.rules {
+		a, b := p.rules[i], other.rules[i]
+		if strings.Join(a.resources, ",") != strings.Join(b.resources, ",") ||
+			strings.Join(a.verbs, " ") != strings.Join(b.verbs, " ") {
+			return false
+		}
+	}
+	return true
+}
+
+func sameItems(a, b []*MenuItem) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesAny(values []string, want string) bool {
+	for _, v := range values {
+		if v == "*" || v == want {
+			return true
+		}
+	}
+	return false
+}
+
+// missingAccess returns a description of what item needs but the user lacks,
+// or an empty string when it is allowed or permissions are not known yet.
+func (nav *OCNavigator) missingAccess(item *MenuItem) string {
+	if nav.perms == nil || nav.config.Permissions == permissionsOff || !item.IsExec {
+		return ""
+	}
+	verb, resource, ok := requiredAccess(item.Command)
+	if ok && nav.apis != nil {
+		if r, found := nav.apis.resolve(resource); found {
+			resource = r.Name
+		}
+	}
+	if !ok || nav.perms.allows(verb, resource) {
+		return ""
+	}
+	return fmt.Sprintf("missing verb %q on resource %q in project %s", verb, resource, nav.perms.project)
+}
+
+// refreshPermissions queries the permissions of the current user in the
+// background and updates the menu when they arrive.
+func (nav *OCNavigator) refreshPermissions() {
+	if nav.config.Permissions == permissionsOff {
+		return
+	}
+	contextName, project := nav.currentContext, nav.currentProject
+	nav.permsSeq++
+	seq := nav.permsSeq
+
+	go func() {
+		output, err := ocOutput(context.Background(), nav.cli, "auth", "can-i", "--list")
+		nav.app.QueueUpdateDraw(func() {
+			if seq != nav.permsSeq {
+				return
+			}
+			var perms *permissions
+			if err != nil {
+				// Unknown permissions never hide anything
+				nav.notify(notifyWarn, "Could not read permissions: "+err.Error())
+			} else {
+				perms = &permissions{context: contextName, project: project, rules: parseCanIList(output)}
+			}
+			if perms.sameRules(nav.perms) {
+				nav.perms = perms
+				return
+			}
+			nav.perms = perms
+			nav.rebuildRootMenu()
+			if index := nav.menuList.GetCurrentItem(); index >= 0 && index < len(nav.currentMenu) {
+				nav.showItemDetails(nav.currentMenu[index])
+			}
+		})
+	}()
+}
+
+// originalItem returns the item of the configured menu that item was copied
+// from when hiding inaccessible entries.
+func (nav *OCNavigator) originalItem(item *MenuItem) *MenuItem {
+	if original, ok := nav.menuOrigins[item]; ok {
+		return original
+	}
+	return item
+}
+
+// hidden reports whether item is left out of the menu, either because the
+// user may not run it or because the cluster does not serve its API.
+func (nav *OCNavigator) hidden(item *MenuItem) bool {
+	if nav.config.Permissions == permissionsHide && nav.missingAccess(item) != "" {
+		return true
+	}
+	return nav.config.MissingAPIs != missingAPIsMark && nav.unsupported(item) != ""
+}
+
+// visibleMenu returns menu without its hidden items, dropping submenus that
+// end up empty.
+func (nav *OCNavigator) visibleMenu(menu []*MenuItem) []*MenuItem {
+	if (nav.config.Permissions != permissionsHide || nav.perms == nil) && nav.apis == nil {
+		return menu
+	}
+
+	var visible []*MenuItem
+	for _, item := range menu {
+		if item.Submenu != nil {
+			submenu := nav.visibleMenu(item.Submenu)
+			if len(submenu) == 0 {
+				continue
+			}
+			if !sameItems(submenu, item.Submenu) {
+				copied := *item
+				copied.Submenu = submenu
+				nav.menuOrigins[&copied] = item
+				item = &copied
+			}
+		} else if nav.hidden(item) {
+			continue
+		}
+		visible = append(visible, item)
+	}
+	return visible
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCanIList(t *testing.T) {
+	output := `Resources                                       Non-Resource URLs   Resource Names   Verbs
+pods                                            []                  []               [get list watch]
+pods/log                                        []                  []               [get]
+deployments.apps,replicasets.apps               []                  []               [*]
+configmaps                                      []                  [my-cm]          [get]
+                                                [/healthz]          []               [get]
+selfsubjectreviews.authentication.k8s.io        []                  []               [create]
+`
+	want := []accessRule{
+		{resources: []string{"pods"}, verbs: []string{"get", "list", "watch"}},
+		{resources: []string{"pods/log"}, verbs: []string{"get"}},
+		{resources: []string{"deployments.apps", "replicasets.apps"}, verbs: []string{"*"}},
+		{resources: []string{"selfsubjectreviews.authentication.k8s.io"}, verbs: []string{"create"}},
+	}
+	if got := parseCanIList(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseCanIList() = %+v, want %+v", got, want)
+	}
+	if got := parseCanIList(""); got != nil {
+		t.Errorf("parseCanIList(\"\") = %+v, want nil", got)
+	}
+}
+
+func TestNormalizeResource(t *testing.T) {
+	tests := map[string]string{
+		"pods":                         "pods",
+		"Pod":                          "pods",
+		"po":                           "pods",
+		"deploy":                       "deployments",
+		"deployments.apps":             "deployments",
+		"ingress":                      "ingresses",
+		"storageclass":                 "storageclasses",
+		"policy":                       "policies",
+		"gateway":                      "gateways",
+		"networkpolicies":              "networkpolicies",
+		"routes.route.openshift.io":    "routes",
+		"clusterversion":               "clusterversions",
+		"ClusterServiceVersion":        "clusterserviceversions",
+		"persistentvolumeclaim":        "persistentvolumeclaims",
+		"horizontalpodautoscaler":      "horizontalpodautoscalers",
+		"mutatingwebhookconfiguration": "mutatingwebhookconfigurations",
+	}
+	for kind, want := range tests {
+		if got := normalizeResource(kind); got != want {
+			t.Errorf("normalizeResource(%q) = %q, want %q", kind, got, want)
+		}
+	}
+}
+
+func TestRequiredAccess(t *testing.T) {
+	tests := []struct {
+		command  string
+		verb     string
+		resource string
+		ok       bool
+	}{
+		{"oc get pods", "list", "pods", true},
+		{"oc get pods -o wide", "list", "pods", true},
+		{"oc get pod web-1", "get", "pods", true},
+		{"oc get pod/web-1", "get", "pods", true},
+		{"oc describe deploy web", "get", "deployments", true},
+		{"oc delete svc web", "delete", "services", true},
+		{"oc edit cm settings", "update", "configmaps", true},
+		{"oc logs web-1", "get", "pods/log", true},
+		{"oc get all", "", "", false},
+		{"oc whoami", "", "", false},
+		{"oc get -A pods", "", "", false},
+		{"kubectl get pods", "", "", false},
+	}
+	for _, tt := range tests {
+		verb, resource, ok := requiredAccess(tt.command)
+		if verb != tt.verb || resource != tt.resource || ok != tt.ok {
+			t.Errorf("requiredAccess(%q) = %q, %q, %v, want %q, %q, %v",
+				tt.command, verb, resource, ok, tt.verb, tt.resource, tt.ok)
+		}
+	}
+}
+
+func TestPermissionsAllows(t *testing.T) {
+	perms := &permissions{rules: []accessRule{
+		{resources: []string{"pods"}, verbs: []string{"get", "list"}},
+		{resources: []string{"pods/*"}, verbs: []string{"get"}},
+		{resources: []string{"deployments.apps"}, verbs: []string{"*"}},
+	}}
+	tests := []struct {
+		verb, resource string
+		want           bool
+	}{
+		{"list", "pods", true},
+		{"delete", "pods", false},
+		{"get", "pods/log", true},
+		{"delete", "deployments", true},
+		{"list", "services", false},
+	}
+	for _, tt := range tests {
+		if got := perms.allows(tt.verb, tt.resource); got != tt.want {
+			t.Errorf("allows(%q, %q) = %v, want %v", tt.verb, tt.resource, got, tt.want)
+		}
+	}
+}
+
+func TestSameRules(t *testing.T) {
+	rules := func(verbs ...string) *permissions {
+		return &permissions{project: "demo", rules: []accessRule{{resources: []string{"pods"}, verbs: verbs}}}
+	}
+	tests := []struct {
+		name string
+		a, b *permissions
+		want bool
+	}{
+		{"both unknown", nil, nil, true},
+		{"one unknown", rules("get"), nil, false},
+		{"same", rules("get", "list"), rules("get", "list"), true},
+		{"other project", rules("get"), &permissions{project: "prod", rules: rules("get").rules}, true},
+		{"other verbs", rules("get", "list"), rules("get"), false},
+		{"more rules", rules("get"), &permissions{rules: append(rules("get").rules, accessRule{resources: []string{"services"}})}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.a.sameRules(tt.b); got != tt.want {
+			t.Errorf("%s: sameRules() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
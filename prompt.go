@@ -0,0 +1,33 @@
+package main
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// prompt replaces the status bar with a one-line input. changed is called on
+// every edit (it may be nil) and done once with the final text and whether
+// the input was accepted with Enter rather than cancelled with Esc. Focus
+// returns to whatever had it before.
+func (nav *OCNavigator) prompt(label, initial string, changed func(text string), done func(text string, accepted bool)) {
+	previousFocus := nav.app.GetFocus()
+
+	input := tview.NewInputField().
+		SetLabel(label).
+		SetText(initial).
+		SetFieldBackgroundColor(tcell.ColorDefault)
+	if changed != nil {
+		input.SetChangedFunc(changed)
+	}
+	input.SetDoneFunc(func(key tcell.Key) {
+		if key != tcell.KeyEnter && key != tcell.KeyEscape {
+			return
+		}
+		nav.bottomBar.RemovePage("prompt")
+		nav.app.SetFocus(previousFocus)
+		done(input.GetText(), key == tcell.KeyEnter)
+	})
+
+	nav.bottomBar.AddAndSwitchToPage("prompt", input, true)
+	nav.app.SetFocus(input)
+}
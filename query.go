@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// maxQueryResults bounds how many results the query box renders.
+const maxQueryResults = 500
+
+// lastJSON is the output of the last command that printed JSON, kept for
+// the query box.
+type lastJSON struct {
+	command string
+	root    *docNode
+}
+
+// looksLikeJSON reports whether output is worth parsing as JSON.
+func looksLikeJSON(output string) bool {
+	output = strings.TrimSpace(output)
+	return strings.HasPrefix(output, "{") && strings.HasSuffix(output, "}") ||
+		strings.HasPrefix(output, "[") && strings.HasSuffix(output, "]")
+}
+
+// showQueryBox evaluates a JSONPath or jq-style query against the last JSON
+// output while it is typed. Enter saves the query as a column for the kind
+// of that output.
+func (nav *OCNavigator) showQueryBox() {
+	last := nav.lastJSON
+	if last == nil {
+		nav.notify(notifyWarn, "No JSON output yet: run an oc get command with -o json or -o yaml")
+		return
+	}
+	kind := nav.columnKind(last.command)
+
+	input := tview.NewInputField().SetLabel("Query: ").SetFieldBackgroundColor(tcell.ColorDefault)
+	results := tview.NewTextView().SetDynamicColors(true).SetScrollable(true).SetWrap(false)
+	status := tview.NewTextView().SetDynamicColors(true)
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(input, 1, 0, true).
+		AddItem(status, 1, 0, false).
+		AddItem(results, 0, 1, false)
+	hint := "Esc close"
+	if kind != "" {
+		hint = "Enter save as " + kind + " column, " + hint
+	}
+	layout.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Query %s (%s) ", tview.Escape(last.command), hint)).
+		SetTitleAlign(tview.AlignLeft)
+
+	run := func(query string) {
+		results.Clear()
+		nodes, err := evalQuery(last.root, query)
+		if err != nil {
+			status.SetText(nav.theme.paint(roleError, tview.Escape(err.Error())))
+			return
+		}
+		count := fmt.Sprintf("%d results", len(nodes))
+		if len(nodes) == 1 {
+			count = "1 result"
+		}
+		status.SetText(nav.theme.paint(roleMuted, count))
+		for i, n := range nodes {
+			if i == maxQueryResults {
+				fmt.Fprintf(results, "%s\n", nav.theme.paint(roleMuted, fmt.Sprintf("… %d more", len(nodes)-i)))
+				break
+			}
+			fmt.Fprintf(results, "%s\n", tview.Escape(queryResultText(n)))
+		}
+		results.ScrollToBeginning()
+	}
+	input.SetChangedFunc(run)
+	input.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEscape:
+			nav.closeOverlay()
+		case tcell.KeyEnter:
+			query := strings.TrimSpace(input.GetText())
+			if kind == "" || query == "" {
+				return
+			}
+			if _, err := evalQuery(last.root, query); err != nil {
+				nav.notify(notifyWarn, "Fix the query before saving it: "+err.Error())
+				return
+			}
+			path := itemPath(query)
+			if items := listItems(last.root); len(items) > 0 && !resolvesOnAny(items, path) {
+				nav.notify(notifyWarn, fmt.Sprintf("%s finds nothing on any item: query all items, such as .items[*].spec.nodeName", path))
+				return
+			}
+			nav.showSaveColumnDialog(kind, path)
+		case tcell.KeyTab:
+			nav.app.SetFocus(results)
+		}
+	})
+	results.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			nav.closeOverlay()
+			return nil
+		case tcell.KeyTab, tcell.KeyBacktab:
+			nav.app.SetFocus(input)
+			return nil
+		}
+		return event
+	})
+
+	run("")
+	nav.showOverlay(layout)
+	nav.app.SetFocus(input)
+}
+
+// showSaveColumnDialog asks for the column set and header to save a query
+// under for kind.
+func (nav *OCNavigator) showSaveColumnDialog(kind, path string) {
+	set := "custom"
+	if sets := nav.config.Columns[kind]; len(sets) > 0 {
+		set = sets[len(sets)-1].Name
+	}
+
+	form := tview.NewForm().
+		AddInputField("Column set: ", set, 30, nil, nil).
+		AddInputField("Header: ", columnHeader(path), 30, nil, nil).
+		AddInputField("Query: ", path, 50, nil, nil)
+	field := func(label string) string {
+		return strings.TrimSpace(form.GetFormItemByLabel(label).(*tview.InputField).GetText())
+	}
+	form.AddButton("Save", func() {
+		set, header, query := field("Column set: "), field("Header: "), field("Query: ")
+		if set == "" || header == "" || query == "" {
+			nav.notify(notifyWarn, "Enter a column set, a header and a query")
+			return
+		}
+		nav.closeOverlay()
+		nav.addColumn(kind, set, Column{Header: header, Path: query})
+	}).
+		AddButton("Cancel", func() {
+			nav.closeOverlay()
+		})
+	form.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Save as %s column ", kind)).
+		SetTitleAlign(tview.AlignLeft)
+	form.SetCancelFunc(nav.closeOverlay)
+	nav.showCenteredOverlay(form, 70, 11)
+}
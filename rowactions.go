@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// rowAction is an operation offered for a single row of the live table.
+type rowAction struct {
+	name string
+	run  func()
+}
+
+// isPodKind reports whether kind, as typed on the oc command line, means pods.
+func isPodKind(kind string) bool {
+	switch kind {
+	case "po", "pod", "pods":
+		return true
+	}
+	return false
+}
+
+// showRowActions opens the actions for the object in row of the live table.
+func (nav *OCNavigator) showRowActions(row int) {
+	lw := nav.live
+	if lw == nil || row < 1 || row > len(lw.keys) {
+		return
+	}
+
+	obj := lw.objects[lw.keys[row-1]]
+	kind := resourceKind(lw.command)
+	name := nestedString(obj, "metadata", "name")
+	namespace := nestedString(obj, "metadata", "namespace")
+	target := fmt.Sprintf("%s %s", kind, name)
+	if namespace != "" {
+		target += " -n " + namespace
+	}
+
+	actions := []rowAction{
+		{"Describe", func() { nav.executeCommand("oc describe " + target) }},
+		{"YAML", func() { nav.executeCommand("oc get " + target + " -o yaml") }},
+		{"Explain schema", func() { nav.showExplainBrowser(kind, "") }},
+	}
+	if isPodKind(kind) {
+		actions = append(actions,
+			rowAction{"Logs", func() { nav.executeCommand(fmt.Sprintf("oc logs %s -n %s", name, namespace)) }},
+			rowAction{"Follow logs", func() { nav.followLogs(name, namespace) }})
+	}
+	actions = append(actions, rowAction{"Delete", func() { nav.showDeleteResourceDialog(target) }})
+
+	list := tview.NewList().ShowSecondaryText(false)
+	for _, action := range actions {
+		list.AddItem(action.name, "", 0, nil)
+	}
+	list.SetSelectedFunc(func(index int, mainText string, secondaryText string, shortcut rune) {
+		nav.closeOverlay()
+		actions[index].run()
+	})
+	list.SetDoneFunc(nav.closeOverlay)
+	list.SetBorder(true).SetTitle(fmt.Sprintf(" %s ", name)).SetTitleAlign(tview.AlignLeft)
+
+	nav.showCenteredOverlay(list, 40, len(actions)+2)
+}
+
+// showDeleteResourceDialog asks for confirmation before deleting target,
+// given as "<kind> <name> [-n <namespace>]".
+func (nav *OCNavigator) showDeleteResourceDialog(target string) {
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Are you sure you want to delete %s?\nThis action cannot be undone!", target)).
+		AddButtons([]string{"Delete", "Cancel"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			nav.closeOverlay()
+			if buttonLabel == "Delete" {
+				nav.executeCommand("oc delete " + target)
+			}
+		})
+
+	nav.showOverlay(modal)
+}
+
+// handleTableMouse opens the row actions on a right click.
+func (nav *OCNavigator) handleTableMouse(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+	if action != tview.MouseRightClick {
+		return action, event
+	}
+
+	row, _ := nav.resourceTable.CellAt(event.Position())
+	if row < 1 {
+		return action, event
+	}
+	nav.app.SetFocus(nav.resourceTable)
+	nav.resourceTable.Select(row, 0)
+	nav.showRowActions(row)
+	return tview.MouseConsumed, nil
+}
+
+// centered places p in the middle of the screen with the given size.
+func centered(p tview.Primitive, width, height int) tview.Primitive {
+	return tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(p, height, 0, true).
+			AddItem(nil, 0, 1, false), width, 0, true).
+		AddItem(nil, 0, 1, false)
+}
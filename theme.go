@@ -0,0 +1,340 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// styleRole names what a piece of text means rather than how it looks.
+type styleRole string
+
+const (
+	roleTitle     styleRole = "title"     // item names and section headings
+	roleCommand   styleRole = "command"   // commands echoed in the output pane
+	roleKey       styleRole = "key"       // key names and field labels
+	roleError     styleRole = "error"     // failures
+	roleSuccess   styleRole = "success"   // hints and positive outcomes
+	roleWarning   styleRole = "warning"   // degraded states such as reconnecting
+	roleContext   styleRole = "context"   // the current cluster context
+	roleProject   styleRole = "project"   // the current project
+	roleMuted     styleRole = "muted"     // separators and secondary details
+	roleHighlight styleRole = "highlight" // rows that changed since the last refresh
+	roleLive      styleRole = "live"      // the live watch indicator
+	roleBorder    styleRole = "border"    // pane borders
+	roleFocus     styleRole = "focus"     // the border of the focused pane
+	roleString    styleRole = "string"    // string values in YAML and JSON
+	roleNumber    styleRole = "number"    // numeric values in YAML and JSON
+	roleBoolean   styleRole = "boolean"   // booleans and null in YAML and JSON
+)
+
+// styleRoles lists every role a theme file may set.
+var styleRoles = []styleRole{roleTitle, roleCommand, roleKey, roleError, roleSuccess, roleWarning,
+	roleContext, roleProject, roleMuted, roleHighlight, roleLive, roleBorder, roleFocus,
+	roleString, roleNumber, roleBoolean}
+
+// Theme maps style roles to tview color tags ("fg:bg:attrs") and sets the
+// base colors of the tview primitives.
+type Theme struct {
+	Name    string
+	colors  map[styleRole]string
+	styles  tview.Theme
+	noColor bool
+}
+
+var darkTheme = Theme{
+	Name: "dark",
+	colors: map[styleRole]string{
+		roleTitle:     "yellow",
+		roleCommand:   "yellow",
+		roleKey:       "cyan",
+		roleError:     "red",
+		roleSuccess:   "green",
+		roleWarning:   "yellow",
+		roleContext:   "cyan",
+		roleProject:   "green",
+		roleMuted:     "gray",
+		roleHighlight: "black:yellow",
+		roleLive:      "red",
+		roleBorder:    "white",
+		roleFocus:     "yellow",
+		roleString:    "green",
+		roleNumber:    "fuchsia",
+		roleBoolean:   "orange",
+	},
+	styles: tview.Styles,
+}
+
+var lightTheme = Theme{
+	Name: "light",
+	colors: map[styleRole]string{
+		roleTitle:     "navy::b",
+		roleCommand:   "purple",
+		roleKey:       "teal",
+		roleError:     "maroon",
+		roleSuccess:   "darkgreen",
+		roleWarning:   "darkorange",
+		roleContext:   "teal",
+		roleProject:   "darkgreen",
+		roleMuted:     "gray",
+		roleHighlight: "white:navy",
+		roleLive:      "maroon",
+		roleBorder:    "gray",
+		roleFocus:     "navy",
+		roleString:    "darkgreen",
+		roleNumber:    "purple",
+		roleBoolean:   "darkorange",
+	},
+	styles: tview.Theme{
+		PrimitiveBackgroundColor:    tcell.ColorWhite,
+		ContrastBackgroundColor:     tcell.ColorLightGray,
+		MoreContrastBackgroundColor: tcell.ColorSilver,
+		BorderColor:                 tcell.ColorGray,
+		TitleColor:                  tcell.ColorNavy,
+		GraphicsColor:               tcell.ColorGray,
+		PrimaryTextColor:            tcell.ColorBlack,
+		SecondaryTextColor:          tcell.ColorNavy,
+		TertiaryTextColor:           tcell.ColorDarkGreen,
+		InverseTextColor:            tcell.ColorWhite,
+		ContrastSecondaryTextColor:  tcell.ColorNavy,
+	},
+}
+
+var highContrastTheme = Theme{
+	Name: "high-contrast",
+	colors: map[styleRole]string{
+		roleTitle:     "yellow::b",
+		roleCommand:   "white::b",
+		roleKey:       "aqua::b",
+		roleError:     "red::b",
+		roleSuccess:   "lime",
+		roleWarning:   "yellow",
+		roleContext:   "aqua",
+		roleProject:   "lime",
+		roleMuted:     "silver",
+		roleHighlight: "black:white",
+		roleLive:      "red::b",
+		roleBorder:    "white",
+		roleFocus:     "yellow",
+		roleString:    "lime",
+		roleNumber:    "fuchsia::b",
+		roleBoolean:   "yellow::b",
+	},
+	styles: tview.Theme{
+		PrimitiveBackgroundColor:    tcell.ColorBlack,
+		ContrastBackgroundColor:     tcell.ColorWhite,
+		MoreContrastBackgroundColor: tcell.ColorYellow,
+		BorderColor:                 tcell.ColorWhite,
+		TitleColor:                  tcell.ColorYellow,
+		GraphicsColor:               tcell.ColorWhite,
+		PrimaryTextColor:            tcell.ColorWhite,
+		SecondaryTextColor:          tcell.ColorYellow,
+		TertiaryTextColor:           tcell.ColorLime,
+		InverseTextColor:            tcell.ColorBlack,
+		ContrastSecondaryTextColor:  tcell.ColorBlack,
+	},
+}
+
+// builtinThemes are the themes that can be selected by name.
+var builtinThemes = map[string]Theme{
+	darkTheme.Name:         darkTheme,
+	lightTheme.Name:        lightTheme,
+	highContrastTheme.Name: highContrastTheme,
+}
+
+// monochromeTheme is used when NO_COLOR is set: no colors at all, with
+// reverse video wherever a color would otherwise carry meaning.
+var monochromeTheme = Theme{
+	Name:    "no-color",
+	colors:  map[styleRole]string{roleHighlight: "::r", roleLive: "::b", roleError: "::b", roleTitle: "::b"},
+	noColor: true,
+	styles: tview.Theme{
+		PrimitiveBackgroundColor:    tcell.ColorDefault,
+		ContrastBackgroundColor:     tcell.ColorDefault,
+		MoreContrastBackgroundColor: tcell.ColorDefault,
+		BorderColor:                 tcell.ColorDefault,
+		TitleColor:                  tcell.ColorDefault,
+		GraphicsColor:               tcell.ColorDefault,
+		PrimaryTextColor:            tcell.ColorDefault,
+		SecondaryTextColor:          tcell.ColorDefault,
+		TertiaryTextColor:           tcell.ColorDefault,
+		InverseTextColor:            tcell.ColorDefault,
+		ContrastSecondaryTextColor:  tcell.ColorDefault,
+	},
+}
+
+// themeFile is the format of a custom theme: a built-in theme to start from
+// and the roles it overrides.
+type themeFile struct {
+	Base   string            `json:"base"`
+	Colors map[string]string `json:"colors"`
+}
+
+// themeNames returns the names of the built-in themes, sorted.
+func themeNames() []string {
+	names := make([]string, 0, len(builtinThemes))
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// loadTheme resolves the configured theme, which is either the name of a
+// built-in theme or the path of a theme file. NO_COLOR wins over both. On
+// error the dark theme is returned along with the error.
+func loadTheme(name string) (Theme, error) {
+	if os.Getenv("NO_COLOR") != "" {
+		return monochromeTheme, nil
+	}
+	if name == "" {
+		return darkTheme, nil
+	}
+	if theme, ok := builtinThemes[name]; ok {
+		return theme, nil
+	}
+	if !strings.ContainsRune(name, os.PathSeparator) && filepath.Ext(name) != ".json" {
+		return darkTheme, fmt.Errorf("unknown theme %q, expected one of %s or a theme file", name, strings.Join(themeNames(), ", "))
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return darkTheme, err
+	}
+	var file themeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return darkTheme, fmt.Errorf("parsing %s: %w", name, err)
+	}
+
+	base := darkTheme
+	if file.Base != "" {
+		var ok bool
+		if base, ok = builtinThemes[file.Base]; !ok {
+			return darkTheme, fmt.Errorf("%s: unknown base theme %q", name, file.Base)
+		}
+	}
+
+	theme := Theme{Name: filepath.Base(name), styles: base.styles, colors: make(map[styleRole]string, len(base.colors))}
+	for role, color := range base.colors {
+		theme.colors[role] = color
+	}
+	var problems []string
+	for role, color := range file.Colors {
+		if !validRole(styleRole(role)) {
+			problems = append(problems, fmt.Sprintf("unknown role %q", role))
+			continue
+		}
+		theme.colors[styleRole(role)] = color
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return theme, fmt.Errorf("%s: %s", name, strings.Join(problems, ", "))
+	}
+	return theme, nil
+}
+
+func validRole(role styleRole) bool {
+	for _, known := range styleRoles {
+		if role == known {
+			return true
+		}
+	}
+	return false
+}
+
+// paint wraps text in the color tag of role. The text is not escaped.
+func (t Theme) paint(role styleRole, text string) string {
+	tag := t.colors[role]
+	if tag == "" {
+		return text
+	}
+	return "[" + tag + "]" + text + "[-:-:-]"
+}
+
+// color returns the foreground color of role, for use outside color tags.
+func (t Theme) color(role styleRole) tcell.Color {
+	if t.noColor {
+		return tcell.ColorDefault
+	}
+	fg, _, _ := strings.Cut(t.colors[role], ":")
+	if fg == "" {
+		return t.styles.PrimaryTextColor
+	}
+	return tcell.GetColor(fg)
+}
+
+// style returns the tcell style of role, for use outside color tags.
+func (t Theme) style(role styleRole) tcell.Style {
+	style := tcell.StyleDefault
+	if fg := t.color(role); fg != tcell.ColorDefault {
+		style = style.Foreground(fg)
+	}
+	fields := strings.Split(t.colors[role], ":")
+	if len(fields) > 1 && fields[1] != "" && !t.noColor {
+		style = style.Background(tcell.GetColor(fields[1]))
+	}
+	if len(fields) > 2 {
+		for _, attr := range fields[2] {
+			switch attr {
+			case 'b':
+				style = style.Bold(true)
+			case 'u':
+				style = style.Underline(true)
+			case 'r':
+				style = style.Reverse(true)
+			case 'd':
+				style = style.Dim(true)
+			case 'i':
+				style = style.Italic(true)
+			}
+		}
+	}
+	return style
+}
+
+// install makes the theme the default for every primitive created afterwards.
+func (t Theme) install() {
+	tview.Styles = t.styles
+	tview.Styles.BorderColor = t.color(roleBorder)
+}
+
+// styleList gives a list a visible selection even without colors.
+func (t Theme) styleList(list *tview.List) {
+	if t.noColor {
+		list.SetSelectedStyle(tcell.StyleDefault.Reverse(true))
+	}
+}
+
+// styleTable gives a table a visible selection even without colors.
+func (t Theme) styleTable(table *tview.Table) {
+	if t.noColor {
+		table.SetSelectedStyle(tcell.StyleDefault.Reverse(true))
+	}
+}
+
+// styleOverlay applies the selection styles to dialogs, which tview draws
+// with background colors that vanish under NO_COLOR.
+func (t Theme) styleOverlay(p tview.Primitive) {
+	if !t.noColor {
+		return
+	}
+	reverse := tcell.StyleDefault.Reverse(true)
+	switch p := p.(type) {
+	case *tview.List:
+		t.styleList(p)
+	case *tview.Table:
+		t.styleTable(p)
+	case *tview.Form:
+		p.SetFieldStyle(tcell.StyleDefault.Underline(true)).
+			SetButtonStyle(tcell.StyleDefault).
+			SetButtonActivatedStyle(reverse)
+	case *tview.Modal:
+		p.SetButtonStyle(tcell.StyleDefault).SetButtonActivatedStyle(reverse)
+	}
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// vimKeys are the keys taken by vi-style navigation when it is enabled.
+var vimKeys = []string{"j", "k", "g", "G", "h", "l", "/", "n", "N", "q", "Ctrl+D", "Ctrl+U"}
+
+// isVimKey reports whether key is one of the vi navigation keys.
+func isVimKey(key string) bool {
+	for _, vimKey := range vimKeys {
+		if vimKey == key {
+			return true
+		}
+	}
+	return false
+}
+
+// searchRegion matches the region tags searchText adds to the command view.
+var searchRegion = regexp.MustCompile(`\["match\d+"\]|\[""\]`)
+
+// searchState holds the matches of the last "/" search in the output pane.
+type searchState struct {
+	query   string
+	matches []string // region IDs in the command view, or row numbers in the table
+	rows    []int
+	current int
+}
+
+// handleVimKey implements vi-style navigation. It returns nil when the key
+// was consumed, or the event, possibly translated, to pass on.
+func (nav *OCNavigator) handleVimKey(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Key() {
+	case tcell.KeyCtrlD:
+		nav.halfPage(1)
+		return nil
+	case tcell.KeyCtrlU:
+		nav.halfPage(-1)
+		return nil
+	case tcell.KeyRune:
+	default:
+		return event
+	}
+	if event.Modifiers()&tcell.ModAlt != 0 {
+		return event
+	}
+
+	switch event.Rune() {
+	case '/':
+		nav.startSearch()
+		return nil
+	case 'n':
+		nav.nextMatch(1)
+		return nil
+	case 'N':
+		nav.nextMatch(-1)
+		return nil
+	}
+
+	// The table and text views understand j/k/g/G/h/l natively
+	if nav.app.GetFocus() != nav.menuList {
+		return event
+	}
+
+	switch event.Rune() {
+	case 'j':
+		return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+	case 'k':
+		return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+	case 'g':
+		return tcell.NewEventKey(tcell.KeyHome, 0, tcell.ModNone)
+	case 'G':
+		return tcell.NewEventKey(tcell.KeyEnd, 0, tcell.ModNone)
+	case 'h':
+		nav.popMenu()
+		return nil
+	case 'l':
+		index := nav.menuList.GetCurrentItem()
+		if index >= 0 && index < len(nav.currentMenu) {
+			nav.onMenuSelect(index, nav.currentMenu[index].Name, nav.currentMenu[index].Description, 0)
+		}
+		return nil
+	}
+	return event
+}
+
+// halfPage moves the focused view by half its height, down for direction 1
+// and up for -1.
+func (nav *OCNavigator) halfPage(direction int) {
+	switch nav.app.GetFocus() {
+	case nav.menuList:
+		_, _, _, height := nav.menuList.GetInnerRect()
+		// Every item takes two lines with its description
+		step := max(1, height/4)
+		index := nav.menuList.GetCurrentItem() + direction*step
+		nav.menuList.SetCurrentItem(min(max(index, 0), nav.menuList.GetItemCount()-1))
+	case nav.resourceTable:
+		_, _, _, height := nav.resourceTable.GetInnerRect()
+		row, _ := nav.resourceTable.GetSelection()
+		row += direction * max(1, height/2)
+		nav.resourceTable.Select(min(max(row, 1), max(1, nav.resourceTable.GetRowCount()-1)), 0)
+	case nav.commandView:
+		_, _, _, height := nav.commandView.GetInnerRect()
+		row, column := nav.commandView.GetScrollOffset()
+		nav.commandView.ScrollTo(max(0, row+direction*max(1, height/2)), column)
+	}
+}
+
+// startSearch prompts for a search in the output pane, the live table when
+// it is shown or the command output otherwise.
+func (nav *OCNavigator) startSearch() {
+	initial := ""
+	if nav.search != nil {
+		initial = nav.search.query
+	}
+	nav.prompt("/", initial, nil, func(query string, accepted bool) {
+		if !accepted || query == "" {
+			return
+		}
+		if nav.live != nil {
+			nav.searchTable(query)
+		} else {
+			nav.searchText(query)
+		}
+		nav.nextMatch(0)
+	})
+}
+
+// searchText marks every line of the command view containing query with a
+// region so that matches can be highlighted and scrolled to.
+func (nav *OCNavigator) searchText(query string) {
+	text := searchRegion.ReplaceAllString(nav.commandView.GetText(false), "")
+	lines := strings.Split(text, "\n")
+	plain := strings.Split(nav.commandView.GetText(true), "\n")
+
+	search := &searchState{query: query}
+	needle := strings.ToLower(query)
+	for i := range lines {
+		if i < len(plain) && strings.Contains(strings.ToLower(plain[i]), needle) {
+			id := fmt.Sprintf("match%d", len(search.matches))
+			lines[i] = fmt.Sprintf(`["%s"]%s[""]`, id, lines[i])
+			search.matches = append(search.matches, id)
+		}
+	}
+
+	nav.search = search
+	nav.commandView.SetRegions(true)
+	nav.commandView.SetText(strings.Join(lines, "\n"))
+}
+
+// clearSearch forgets the last search and its highlights, for when the
+// command view gets new output.
+func (nav *OCNavigator) clearSearch() {
+	nav.search = nil
+	nav.commandView.Highlight().SetRegions(false)
+}
+
+// searchTable collects the rows of the live table containing query.
+func (nav *OCNavigator) searchTable(query string) {
+	search := &searchState{query: query}
+	needle := strings.ToLower(query)
+	for row := 1; row < nav.resourceTable.GetRowCount(); row++ {
+		for column := 0; column < nav.resourceTable.GetColumnCount(); column++ {
+			cell := nav.resourceTable.GetCell(row, column)
+			if strings.Contains(strings.ToLower(cell.Text), needle) {
+				search.rows = append(search.rows, row)
+				break
+			}
+		}
+	}
+	nav.search = search
+}
+
+// nextMatch moves to the next (1) or previous (-1) match of the last search,
+// or shows the current one for 0.
+func (nav *OCNavigator) nextMatch(direction int) {
+	search := nav.search
+	if search == nil {
+		return
+	}
+
+	count := len(search.matches) + len(search.rows)
+	if count == 0 {
+		nav.notify(notifyWarn, fmt.Sprintf("Pattern not found: %s", search.query))
+		return
+	}
+	search.current = (search.current + direction + count) % count
+
+	if len(search.rows) > 0 {
+		nav.resourceTable.Select(search.rows[search.current], 0)
+	} else {
+		nav.commandView.Highlight(search.matches[search.current]).ScrollToHighlight()
+	}
+	nav.notify(notifyInfo, fmt.Sprintf("Match %d/%d for %s", search.current+1, count, search.query))
+}
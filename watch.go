@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+const defaultWatchInterval = 2 * time.Second
+
+// watchState tracks a running watch loop that periodically re-executes a
+// list command and renders its output into the command view.
+type watchState struct {
+	command  string
+	interval time.Duration
+	stop     chan struct{}
+	previous map[string]string
+}
+
+// isListCommand reports whether command is an "oc get" style list command
+// that makes sense to re-run periodically.
+func isListCommand(command string) bool {
+	fields := strings.Fields(command)
+	return len(fields) >= 2 && fields[0] == "oc" && fields[1] == "get"
+}
+
+// toggleWatch starts watching the last executed list command, or stops the
+// current watch if one is running.
+func (nav *OCNavigator) toggleWatch() {
+	if nav.watch != nil {
+		nav.stopWatch()
+		nav.notify(notifyInfo, "Watch stopped")
+		return
+	}
+
+	if nav.lastListCommand == "" {
+		nav.notify(notifyWarn, "Nothing to watch: run a list command first")
+		return
+	}
+
+	nav.startWatch(nav.lastListCommand)
+}
+
+// startWatch re-runs command every watchInterval until stopWatch is called.
+// The command itself runs off the event loop; rendering is queued back onto it.
+func (nav *OCNavigator) startWatch(command string) {
+	w := &watchState{
+		command:  command,
+		interval: nav.watchInterval,
+		stop:     make(chan struct{}),
+	}
+	nav.watch = w
+	nav.closeDocument()
+	nav.notify(notifyInfo, fmt.Sprintf("Watching every %s: %s", w.interval, command))
+
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			output, _, err := runCommand(nav.cli, command)
+			nav.app.QueueUpdateDraw(func() {
+				// Ignore results that arrive after the watch was stopped or replaced.
+				if nav.watch == w {
+					nav.renderWatch(w, output, err)
+				}
+			})
+
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// stopWatch stops the running watch, if any, and restores the output title.
+func (nav *OCNavigator) stopWatch() {
+	if nav.watch == nil {
+		return
+	}
+	close(nav.watch.stop)
+	nav.watch = nil
+	nav.commandView.SetTitle(" Command Output ")
+}
+
+// renderWatch replaces the command view content with a fresh result while
+// keeping the scroll position, and highlights rows that changed since the
+// previous refresh.
+func (nav *OCNavigator) renderWatch(w *watchState, output string, err error) {
+	row, column := nav.commandView.GetScrollOffset()
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "%s\n\n", nav.theme.paint(roleCommand, "$ "+tview.Escape(w.command)))
+	if err != nil {
+		fmt.Fprintf(&text, "%s\n\n", nav.theme.paint(roleError, fmt.Sprintf("Error: %v", err)))
+	}
+
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	keyFields := 1
+	if len(lines) > 0 && strings.HasPrefix(lines[0], "NAMESPACE") {
+		keyFields = 2
+	}
+
+	current := make(map[string]string, len(lines))
+	for i, line := range lines {
+		key := rowKey(line, keyFields)
+		current[key] = line
+
+		previous, seen := w.previous[key]
+		if i > 0 && w.previous != nil && (!seen || previous != line) {
+			fmt.Fprintf(&text, "%s\n", nav.theme.paint(roleHighlight, tview.Escape(line)))
+		} else {
+			fmt.Fprintf(&text, "%s\n", tview.Escape(line))
+		}
+	}
+	w.previous = current
+
+	nav.commandView.SetText(text.String())
+	nav.commandView.ScrollTo(row, column)
+	nav.commandView.SetTitle(fmt.Sprintf(" Command Output (watch every %s, last refresh %s) ",
+		w.interval, time.Now().Format("15:04:05")))
+}
+
+// rowKey identifies a row of tabular oc output by its leading name columns so
+// that rows can be matched across refreshes.
+func rowKey(line string, keyFields int) string {
+	fields := strings.Fields(line)
+	if len(fields) > keyFields {
+		fields = fields[:keyFields]
+	}
+	return strings.Join(fields, " ")
+}